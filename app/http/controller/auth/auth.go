@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
 	"net/smtp"
 	"os"
 	"time"
@@ -14,7 +13,9 @@ import (
 	"github.com/arif-x/sqlx-mysql-boilerplate/config"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
 	hash "github.com/arif-x/sqlx-mysql-boilerplate/pkg/hash"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/logger"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
 	jwt "github.com/form3tech-oss/jwt-go"
 	"github.com/gofiber/fiber/v2"
 	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
@@ -34,13 +35,14 @@ import (
 // @Param email formData string true "Email"
 // @Param password formData string true "Password" format(password)
 // @Failure 400,401,403,500 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Success 200 {object} response.AuthWithPermissionResponse
 // @Router /api/v1/auth/register [post]
 func Register(c *fiber.Ctx) error {
 	register := &model.Register{}
 
-	if err := c.BodyParser(register); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, register); err != nil {
+		return response.Invalid(c, err)
 	}
 
 	password, err := hash.Hash([]byte(register.Password))
@@ -83,20 +85,21 @@ func Register(c *fiber.Ctx) error {
 // @Param username formData string true "Username Or Email"
 // @Param password formData string true "Password" format(password)
 // @Failure 400,401,403,500 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Success 200 {object} response.AuthWithPermissionResponse
 // @Router /api/v1/auth/login [post]
 func Login(c *fiber.Ctx) error {
 	login := &model.Login{}
 
-	if err := c.BodyParser(login); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, login); err != nil {
+		return response.Invalid(c, err)
 	}
 
 	repository := repo.NewAuthRepo(database.GetDB())
 	user, role_name, permission, err := repository.Login(login.Username)
 
 	if err != nil {
-		log.Println(err)
+		logger.Request(c).WithError(err).Info("login failed")
 		if err == sql.ErrNoRows {
 			return response.InvalidCredential(c, errors.New("No credential"))
 		} else {
@@ -144,39 +147,23 @@ func SendEmail(c *fiber.Ctx) error {
 	email_verified_at := claims["email_verified_at"]
 
 	if email_verified_at != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  false,
-			"message": "Your email has already verified!",
-			"data":    nil,
-		})
+		return response.Fail(c, response.NewError(fiber.StatusConflict, response.CodeEmailVerified, "Your email has already verified!"))
 	}
 
 	email, ok := user_email.(string)
 	if !ok {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  false,
-			"message": "Failed to send email!",
-			"data":    nil,
-		})
+		return response.Fail(c, response.NewError(fiber.StatusInternalServerError, response.CodeEmailNotSent, "Failed to send email!"))
 	}
 
 	token, ok := email_token.(string)
 	if !ok {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  false,
-			"message": "Failed to send email!",
-			"data":    nil,
-		})
+		return response.Fail(c, response.NewError(fiber.StatusInternalServerError, response.CodeEmailNotSent, "Failed to send email!"))
 	}
 
 	err := SendVerificationEmail(email, token)
 	if err != nil {
-		log.Println("Error sending verification email:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  false,
-			"message": "Failed to send email!",
-			"data":    nil,
-		})
+		logger.Request(c).WithError(err).Error("failed sending verification email")
+		return response.Fail(c, response.NewError(fiber.StatusInternalServerError, response.CodeEmailNotSent, "Failed to send email!").WithCause(err))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -206,22 +193,14 @@ func Verify(c *fiber.Ctx) error {
 	email_token := claims["email_token"]
 
 	if token != email_token {
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{
-			"status":  false,
-			"message": "Invalid email code!",
-			"data":    "OK",
-		})
+		return response.Fail(c, response.NewError(fiber.StatusBadRequest, response.CodeInvalidCode, "Invalid email code!"))
 	}
 
 	repository := repo.NewAuthRepo(database.GetDB())
 	user_data, role_name, permission, err := repository.Verify(username.(string))
 
 	if err != nil {
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{
-			"status":  false,
-			"message": "Can't verify your email!",
-			"data":    "OK",
-		})
+		return response.Fail(c, response.NewError(fiber.StatusInternalServerError, response.CodeInternal, "Can't verify your email!").WithCause(err))
 	}
 
 	token_data, _, err := GenerateNewAccessToken(user_data.UUID, user_data.Username, user_data.Email, user_data.Name, user_data.IsActive, user_data.EmailVerifiedAt, user_data.RoleUUID, permission)
@@ -249,13 +228,14 @@ func Verify(c *fiber.Ctx) error {
 // @Produce json
 // @Param username formData string true "Email/Username" default(superadmin)
 // @Failure 400,401,403,500 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Success 200 {object} response.AuthWithPermissionResponse
 // @Router /api/v1/auth/send-password-email [post]
 func SendForgotPasswordEmail(c *fiber.Ctx) error {
 	forgot_password := &model.ForgotPassword{}
 
-	if err := c.BodyParser(forgot_password); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, forgot_password); err != nil {
+		return response.Invalid(c, err)
 	}
 
 	repository := repo.NewAuthRepo(database.GetDB())
@@ -273,12 +253,8 @@ func SendForgotPasswordEmail(c *fiber.Ctx) error {
 
 	err = SendPasswordEmail(user_data.Email, forgor_password_token)
 	if err != nil {
-		log.Println("Error sending forgot password verification email:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  false,
-			"message": "Failed to send email!",
-			"data":    nil,
-		})
+		logger.Request(c).WithError(err).Error("failed sending forgot password email")
+		return response.Fail(c, response.NewError(fiber.StatusInternalServerError, response.CodeEmailNotSent, "Failed to send email!").WithCause(err))
 	}
 
 	jwt_expired_at := config.AppCfg().JWTSecretExpireMinutesCount
@@ -311,11 +287,7 @@ func CheckForgotPasswordToken(c *fiber.Ctx) error {
 	forgot_password_token := claims["forgot_password_token"]
 
 	if token != forgot_password_token {
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{
-			"status":  false,
-			"message": "Invalid email code!",
-			"data":    false,
-		})
+		return response.Fail(c, response.NewError(fiber.StatusBadRequest, response.CodeInvalidCode, "Invalid email code!"))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -334,6 +306,7 @@ func CheckForgotPasswordToken(c *fiber.Ctx) error {
 // @Param password formData string true "New Password" format(password)
 // @Security ApiKeyAuth
 // @Failure 400,401,403,500 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Success 200 {object} response.AuthWithPermissionResponse
 // @Router /api/v1/auth/change-forgot-password [post]
 func ChangeForgotPassword(c *fiber.Ctx) error {
@@ -344,8 +317,8 @@ func ChangeForgotPassword(c *fiber.Ctx) error {
 
 	change_forgot_password := &model.ChangeForgotPassword{}
 
-	if err := c.BodyParser(change_forgot_password); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, change_forgot_password); err != nil {
+		return response.Invalid(c, err)
 	}
 
 	// if change_forgot_password.Password != forgot_password_token {
@@ -367,11 +340,7 @@ func ChangeForgotPassword(c *fiber.Ctx) error {
 	user_data, role_name, permission, err := repository.ChangeForgotPassword(username.(string), change_forgot_password.Password)
 
 	if err != nil {
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{
-			"status":  false,
-			"message": "Can't change your password!",
-			"data":    "OK",
-		})
+		return response.Fail(c, response.NewError(fiber.StatusInternalServerError, response.CodeInternal, "Can't change your password!").WithCause(err))
 	}
 
 	token_data, _, err := GenerateNewAccessToken(user_data.UUID, user_data.Username, user_data.Email, user_data.Name, user_data.IsActive, user_data.EmailVerifiedAt, user_data.RoleUUID, permission)
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/auth"
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	jwt "github.com/form3tech-oss/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// StopImpersonation method for ending an impersonation session.
+// @Description end an impersonation session. The impersonation token stops working, the impersonator goes on with their own token.
+// @Summary end impersonation.
+// @Tags Auth
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Failure 400,401,403,500 {object} response.ErrorResponse "Error"
+// @Success 200 {object} response.AuthResponse
+// @Router /api/v1/auth/impersonate/stop [post]
+func StopImpersonation(c *fiber.Ctx) error {
+	user := c.Locals("user").(*JWTTokenAuthed.Token)
+	claims := user.Claims.(JWTTokenAuthed.MapClaims)
+	impersonator_id, ok := claims["impersonator_id"].(string)
+	impersonation_id, _ := claims["jti"].(string)
+
+	if !ok || impersonator_id == "" || impersonation_id == "" {
+		return response.BadRequest(c, errors.New("You are not impersonating anyone!"))
+	}
+
+	repository := repo.NewAuthRepo(database.GetDB())
+	stopped, err := repository.StopImpersonation(impersonation_id)
+
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+	if !stopped {
+		return response.BadRequest(c, errors.New("The impersonation has already ended!"))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Impersonation ended, use your own token again",
+		"data":    "OK",
+	})
+}
+
+// GenerateImpersonationToken signs a short-lived token carrying the impersonated user's identity
+// along with the impersonator_id claim of the staff member acting on their behalf. Its jti is the
+// impersonation session, which ends the token when stopped.
+func GenerateImpersonationToken(UserID uuid.UUID, Username string, Email string, Name string, IsActive bool, EmailVerifiedAt *time.Time, RoleUUID string, Permission []string, ImpersonatorID string, ImpersonationID string) (string, time.Time, error) {
+	token := jwt.New(jwt.SigningMethodHS256)
+	expires := time.Now().Add(time.Minute * time.Duration(config.AppCfg().ImpersonationExpireMinutesCount))
+
+	claims := token.Claims.(jwt.MapClaims)
+	claims["user_id"] = UserID.String()
+	claims["username"] = Username
+	claims["email"] = Email
+	claims["name"] = Name
+	claims["is_active"] = IsActive
+	claims["email_verified_at"] = EmailVerifiedAt
+	claims["role_uuid"] = RoleUUID
+	claims["permission"] = Permission
+	claims["impersonator_id"] = ImpersonatorID
+	claims["jti"] = ImpersonationID
+	claims["exp"] = expires.Unix()
+
+	t, err := token.SignedString([]byte(config.AppCfg().JWTSecretKey))
+	if err != nil {
+		return "", expires, err
+	}
+
+	return t, expires, nil
+}
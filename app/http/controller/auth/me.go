@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"strings"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/auth"
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/auth"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
+	"github.com/gofiber/fiber/v2"
+	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
+)
+
+// MePermissions method for getting the effective permissions of the current user.
+// @Description get the effective permissions of the current user grouped by resource.
+// @Summary get current user permissions.
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Failure 400,401,403,500 {object} response.ErrorResponse "Error"
+// @Success 200 {object} response.MePermissionsResponse
+// @Router /api/v1/me/permissions [get]
+func MePermissions(c *fiber.Ctx) error {
+	user := c.Locals("user").(*JWTTokenAuthed.Token)
+	claims := user.Claims.(JWTTokenAuthed.MapClaims)
+	user_id, _ := claims["user_id"].(string)
+
+	repository := repo.NewAuthRepo(database.GetDB())
+	permissions, err := repository.Permissions(user_id)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	grouped := map[string][]string{}
+	for _, permission := range permissions {
+		resource, action := SplitPermission(permission)
+		grouped[resource] = append(grouped[resource], action)
+	}
+
+	return response.Show(c, grouped)
+}
+
+// MeCan method for checking a list of permissions for the current user.
+// @Description check a list of permissions. A resource_uuid given with a "-own" permission, such as post-update-own, also needs the current user to own that resource.
+// @Summary check current user permissions.
+// @Tags Me
+// @Accept multipart/form-data
+// @Produce json
+// @Param permission formData []string true "Permission Name" collectionFormat(multi)
+// @Param resource_uuid formData []string false "Resource UUID per permission" collectionFormat(multi)
+// @Security ApiKeyAuth
+// @Failure 400,401,403,500 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
+// @Success 200 {object} response.MeCanResponse
+// @Router /api/v1/me/can [post]
+func MeCan(c *fiber.Ctx) error {
+	can := &model.Can{}
+
+	if err := validation.ParseBody(c, can); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	user := c.Locals("user").(*JWTTokenAuthed.Token)
+	claims := user.Claims.(JWTTokenAuthed.MapClaims)
+	user_id, _ := claims["user_id"].(string)
+
+	repository := repo.NewAuthRepo(database.GetDB())
+	permissions, err := repository.Permissions(user_id)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	owned := make(map[string]struct{}, len(permissions))
+	for _, permission := range permissions {
+		owned[permission] = struct{}{}
+	}
+
+	result := map[string]bool{}
+	for i, permission := range can.Permission {
+		_, allowed := owned[permission]
+
+		key := permission
+		if i < len(can.ResourceUUID) && can.ResourceUUID[i] != "" {
+			key = permission + ":" + can.ResourceUUID[i]
+			if resource, own := OwnPermission(permission); allowed && own {
+				allowed, err = repository.IsOwner(resource, can.ResourceUUID[i], user_id)
+				// Resources without owners can't be owned.
+				if err == repo.ErrNoOwner {
+					allowed = false
+				} else if err != nil {
+					return response.InternalServerError(c, err)
+				}
+			}
+		}
+
+		result[key] = allowed
+	}
+
+	return response.Show(c, result)
+}
+
+// OwnPermission tells whether a permission only covers resources the user owns, named
+// "resource-action-own", and returns the resource it is about.
+func OwnPermission(permission string) (string, bool) {
+	if !strings.HasSuffix(permission, "-own") {
+		return "", false
+	}
+	resource, _ := SplitPermission(strings.TrimSuffix(permission, "-own"))
+	return resource, true
+}
+
+// SplitPermission splits a "resource-action" permission name on its last hyphen,
+// so "sync-permission-index" becomes "sync-permission" and "index".
+func SplitPermission(permission string) (string, string) {
+	i := strings.LastIndex(permission, "-")
+	if i < 0 {
+		return permission, ""
+	}
+	return permission[:i], permission[i+1:]
+}
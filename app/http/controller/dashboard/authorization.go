@@ -0,0 +1,75 @@
+package dashboard
+
+import (
+	"errors"
+
+	authRepo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/auth"
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/gofiber/fiber/v2"
+	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
+)
+
+var errPrivilegeEscalation = errors.New("You can't grant or manage permissions you don't have!")
+
+// actorID returns the UUID of the authenticated user.
+func actorID(c *fiber.Ctx) string {
+	user := c.Locals("user").(*JWTTokenAuthed.Token)
+	claims := user.Claims.(JWTTokenAuthed.MapClaims)
+	user_id, _ := claims["user_id"].(string)
+
+	return user_id
+}
+
+// actorPermissions loads the permissions the authenticated user holds right now,
+// rather than trusting the permission list baked into the token.
+func actorPermissions(c *fiber.Ctx) ([]string, error) {
+	repository := authRepo.NewAuthRepo(database.GetDB())
+	return repository.Permissions(actorID(c))
+}
+
+// isSubset reports whether every permission in permissions is also in of.
+func isSubset(permissions []string, of []string) bool {
+	owned := make(map[string]struct{}, len(of))
+	for _, permission := range of {
+		owned[permission] = struct{}{}
+	}
+
+	for _, permission := range permissions {
+		if _, ok := owned[permission]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// canAssignRole reports whether the authenticated user holds every permission of the role.
+func canAssignRole(c *fiber.Ctx, role_uuid string) (bool, error) {
+	actor, err := actorPermissions(c)
+	if err != nil {
+		return false, err
+	}
+
+	role, err := repo.NewRoleRepo(database.GetDB()).Permissions(role_uuid)
+	if err != nil {
+		return false, err
+	}
+
+	return isSubset(role, actor), nil
+}
+
+// canManageUser reports whether the authenticated user holds every permission of the target user.
+func canManageUser(c *fiber.Ctx, user_uuid string) (bool, error) {
+	actor, err := actorPermissions(c)
+	if err != nil {
+		return false, err
+	}
+
+	target, err := authRepo.NewAuthRepo(database.GetDB()).Permissions(user_uuid)
+	if err != nil {
+		return false, err
+	}
+
+	return isSubset(target, actor), nil
+}
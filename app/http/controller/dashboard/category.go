@@ -0,0 +1,178 @@
+package dashboard
+
+import (
+	"database/sql"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/paginate"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CategoryIndex func gets all category.
+// @Description Get all category.
+// @Summary Get all category
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Param page query integer false "Page"
+// @Param limit query integer false "Limit"
+// @Param search query string false "Search"
+// @Param sort_by query string false "Sort By" Enums(id, name)
+// @Param sort query string false "Sort" Enums(ASC, DESC)
+// @Success 200 {object} response.CategoriesResponse
+// @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/category [get]
+func CategoryIndex(c *fiber.Ctx) error {
+	page, limit, search, sort_by, sort := paginate.Paginate(c)
+	repository := repo.NewCategoryRepo(database.GetDB())
+
+	categories, count, err := repository.Index(limit, uint(limit*(page-1)), search, sort_by, sort)
+
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	return response.Index(c, page, limit, count, categories)
+}
+
+// CategoryShow func gets single category.
+// @Description Get single category with its depth in the tree.
+// @Summary Get single category
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} response.CategoryShowResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/category/{id} [get]
+func CategoryShow(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	repository := repo.NewCategoryRepo(database.GetDB())
+	category, err := repository.Show(ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Show(c, category)
+}
+
+// CategoryStore func create category.
+// @Description Create category, optionally under a parent category.
+// @Summary Create category
+// @Tags Category
+// @Accept multipart/form-data
+// @Produce json
+// @Param parent_uuid formData string false "Parent Category UUID"
+// @Param name formData string true "Name" default(Category Name)
+// @Param is_active formData bool true "Is Active"
+// @Success 200 {object} response.CategoryResponse
+// @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/category [post]
+func CategoryStore(c *fiber.Ctx) error {
+	category := &model.StoreCategory{}
+
+	if err := validation.ParseBody(c, category); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	repository := repo.NewCategoryRepo(database.GetDB())
+
+	category.Slug = repository.GetSlug(category.Name, nil)
+
+	res, err := repository.Store(category)
+
+	if err != nil {
+		if err == repo.ErrCategoryNotFound || err == repo.ErrCategoryTooDeep {
+			return response.BadRequest(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Store(c, res)
+}
+
+// CategoryUpdate func update category.
+// @Description Update category. Moving it under another parent moves its whole subtree.
+// @Summary Update category
+// @Tags Category
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param parent_uuid formData string false "Parent Category UUID, empty for a root category"
+// @Param name formData string true "Name" default(Category Name Update)
+// @Param is_active formData bool true "Is Active"
+// @Success 200 {object} response.CategoryResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/category/{id} [put]
+func CategoryUpdate(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	category := &model.UpdateCategory{}
+
+	if err := validation.ParseBody(c, category); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	repository := repo.NewCategoryRepo(database.GetDB())
+
+	category.Slug = repository.GetSlug(category.Name, &ID)
+
+	res, err := repository.Update(ID, category)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else if err == repo.ErrCategoryNotFound || err == repo.ErrCategoryTooDeep || err == repo.ErrCategoryCycle {
+			return response.BadRequest(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Update(c, res)
+}
+
+// CategoryDestroy func delete category.
+// @Description Delete category. Its children move up to its parent and its posts are left without a category.
+// @Summary Delete category
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} response.CategoryResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/category/{id} [delete]
+func CategoryDestroy(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	repository := repo.NewCategoryRepo(database.GetDB())
+	res, err := repository.Destroy(ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Destroy(c, res)
+}
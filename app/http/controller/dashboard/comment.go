@@ -0,0 +1,238 @@
+package dashboard
+
+import (
+	"database/sql"
+	"errors"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/paginate"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/related"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CommentIndex func gets all comment.
+// @Description Get all comment.
+// @Summary Get all comment
+// @Tags Comment
+// @Accept json
+// @Produce json
+// @Param page query integer false "Page"
+// @Param limit query integer false "Limit"
+// @Param search query string false "Search"
+// @Param status query string false "Status" Enums(pending, approved, rejected)
+// @Param sort_by query string false "Sort By" Enums(comments.id, comments.created_at)
+// @Param sort query string false "Sort" Enums(ASC, DESC)
+// @Success 200 {object} response.CommentsResponse
+// @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/comment [get]
+func CommentIndex(c *fiber.Ctx) error {
+	page, limit, search, sort_by, sort := paginate.Paginate(c)
+
+	repository := repo.NewCommentRepo(database.GetDB())
+
+	comments, count, err := repository.Index(limit, uint(limit*(page-1)), search, sort_by, sort, c.Query("status"))
+
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	return response.Index(c, page, limit, count, comments)
+}
+
+// CommentShow func gets single comment.
+// @Description Get single comment.
+// @Summary Get single comment
+// @Tags Comment
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {object} response.CommentResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/comment/{id} [get]
+func CommentShow(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	repository := repo.NewCommentRepo(database.GetDB())
+	comment, err := repository.Show(ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Show(c, comment)
+}
+
+// CommentUpdate func update comment.
+// @Description Edit the content of a comment.
+// @Summary Update comment
+// @Tags Comment
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param content formData string true "Content"
+// @Success 200 {object} response.CommentResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/comment/{id} [put]
+func CommentUpdate(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	comment := &model.UpdateComment{}
+
+	if err := validation.ParseBody(c, comment); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	if comment.Content == "" {
+		return response.BadRequest(c, errors.New("content is required"))
+	}
+
+	repository := repo.NewCommentRepo(database.GetDB())
+	res, err := repository.Update(ID, comment)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Update(c, res)
+}
+
+// CommentApprove func approve comment.
+// @Description Approve a comment so it shows on the public post.
+// @Summary Approve comment
+// @Tags Comment
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {object} response.CommentResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/comment/{id}/approve [put]
+func CommentApprove(c *fiber.Ctx) error {
+	return moderateComment(c, model.CommentStatusApproved)
+}
+
+// CommentReject func reject comment.
+// @Description Reject a comment so it's hidden from the public post.
+// @Summary Reject comment
+// @Tags Comment
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {object} response.CommentResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/comment/{id}/reject [put]
+func CommentReject(c *fiber.Ctx) error {
+	return moderateComment(c, model.CommentStatusRejected)
+}
+
+func moderateComment(c *fiber.Ctx, status string) error {
+	ID := c.Params("id")
+
+	repository := repo.NewCommentRepo(database.GetDB())
+	res, err := repository.Moderate(ID, status)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	related.Invalidate()
+
+	return response.Update(c, res)
+}
+
+// CommentBulk func moderate many comment.
+// @Description Approve, reject or delete many comments at once. Deleting also needs comment-destroy.
+// @Summary Bulk moderate comment
+// @Tags Comment
+// @Accept multipart/form-data
+// @Produce json
+// @Param uuid formData []string true "Comment UUIDs" collectionFormat(multi)
+// @Param action formData string true "Action" Enums(approve, reject, delete)
+// @Success 200 {object} response.CommentBulkResponse
+// @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/comment/bulk [post]
+func CommentBulk(c *fiber.Ctx) error {
+	bulk := &model.BulkComment{}
+
+	if err := validation.ParseBody(c, bulk); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	if bulk.Action == "delete" {
+		actor, err := actorPermissions(c)
+		if err != nil {
+			return response.InternalServerError(c, err)
+		}
+		if !isSubset([]string{"comment-destroy"}, actor) {
+			return response.Forbidden(c, errors.New("You dont have permission to delete comments!"))
+		}
+	}
+
+	repository := repo.NewCommentRepo(database.GetDB())
+	affected, err := repository.Bulk(bulk.UUID, bulk.Action)
+
+	if err != nil {
+		if err == repo.ErrInvalidCommentAction {
+			return response.BadRequest(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	related.Invalidate()
+
+	return response.Update(c, fiber.Map{"affected": affected})
+}
+
+// CommentDestroy func delete comment.
+// @Description Delete comment.
+// @Summary Delete comment
+// @Tags Comment
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {object} response.CommentResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/comment/{id} [delete]
+func CommentDestroy(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	repository := repo.NewCommentRepo(database.GetDB())
+	res, err := repository.Destroy(ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	related.Invalidate()
+
+	return response.Destroy(c, res)
+}
@@ -0,0 +1,309 @@
+package dashboard
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	fileHelper "github.com/arif-x/sqlx-mysql-boilerplate/pkg/file"
+	jsonutil "github.com/arif-x/sqlx-mysql-boilerplate/pkg/json"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/logger"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/paginate"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MediaIndex func gets all media.
+// @Description Get all media with how many posts use each.
+// @Summary Get all media
+// @Tags Media
+// @Accept json
+// @Produce json
+// @Param page query integer false "Page"
+// @Param limit query integer false "Limit"
+// @Param search query string false "Search in filename, alt text and caption"
+// @Param type query string false "Content type or prefix" default(image/)
+// @Param sort_by query string false "Sort By" Enums(id, filename, size, created_at)
+// @Param sort query string false "Sort" Enums(ASC, DESC)
+// @Success 200 {object} response.MediasResponse
+// @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/media [get]
+func MediaIndex(c *fiber.Ctx) error {
+	page, limit, search, sort_by, sort := paginate.Paginate(c)
+	repository := repo.NewMediaRepo(database.GetDB())
+
+	if sort_by == "id" {
+		sort_by = "media.id"
+	}
+
+	media, count, err := repository.Index(limit, uint(limit*(page-1)), search, c.Query("type"), sort_by, sort)
+
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	for i := range media {
+		if err := linkMedia(&media[i]); err != nil {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Index(c, page, limit, count, media)
+}
+
+// MediaShow func gets single media.
+// @Description Get single media.
+// @Summary Get single media
+// @Tags Media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} response.MediaResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/media/{id} [get]
+func MediaShow(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	repository := repo.NewMediaRepo(database.GetDB())
+	media, err := repository.Show(ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	if err := linkMedia(&media); err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	return response.Show(c, media)
+}
+
+// MediaStore func upload media.
+// @Description Upload a file to the media library. Images get the configured variants. Private media
+// @Description is returned with signed links that expire, and can't be a post thumbnail.
+// @Summary Upload media
+// @Tags Media
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "File, a JPEG, PNG, GIF or SVG image"
+// @Param alt_text formData string false "Alt Text"
+// @Param caption formData string false "Caption"
+// @Param is_private formData bool false "Private, only reachable through signed links that expire"
+// @Success 200 {object} response.MediaResponse
+// @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Failure 413,415 {object} response.UploadErrorResponse "Rejected file"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/media [post]
+func MediaStore(c *fiber.Ctx) error {
+	request := &model.StoreMedia{}
+
+	if err := validation.ParseBody(c, request); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	media, err := storeMedia(file, "file", fileHelper.MediaRule(), mediaPrefix(request.IsPrivate), model.MediaOriginLibrary, actorID(c))
+	if err != nil {
+		return uploadRejected(c, err)
+	}
+
+	if request.AltText != "" || request.Caption != "" {
+		media, err = repo.NewMediaRepo(database.GetDB()).Update(media.UUID.String(), &model.UpdateMedia{AltText: request.AltText, Caption: request.Caption})
+		if err != nil {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	if err := linkMedia(&media); err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	return response.Store(c, media)
+}
+
+// MediaUpdate func update media.
+// @Description Update the alt text and caption of media.
+// @Summary Update media
+// @Tags Media
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param alt_text formData string false "Alt Text"
+// @Param caption formData string false "Caption"
+// @Success 200 {object} response.MediaResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/media/{id} [put]
+func MediaUpdate(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	media := &model.UpdateMedia{}
+
+	if err := validation.ParseBody(c, media); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	repository := repo.NewMediaRepo(database.GetDB())
+	res, err := repository.Update(ID, media)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	if err := linkMedia(&res); err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	return response.Update(c, res)
+}
+
+// MediaDestroy func delete media.
+// @Description Delete media and its files. Media used by a post can't be deleted.
+// @Summary Delete media
+// @Tags Media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} response.MediaResponse
+// @Failure 400,401,403,404,409 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/media/{id} [delete]
+func MediaDestroy(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	repository := repo.NewMediaRepo(database.GetDB())
+	res, err := repository.Destroy(ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else if err == repo.ErrMediaInUse {
+			return response.Conflict(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	// The row is gone either way, files left behind are removed by media:prune.
+	if err := deleteMediaFiles(res); err != nil {
+		logger.Request(c).WithError(err).Error("failed deleting media files")
+	}
+
+	return response.Destroy(c, res)
+}
+
+// storeMedia validates an uploaded file against rule and saves it as media. The extension follows
+// the detected content type, whatever the uploaded file was called.
+func storeMedia(file *multipart.FileHeader, field string, rule fileHelper.Rule, prefix string, origin string, UserUUID string) (model.Media, error) {
+	upload, err := fileHelper.Validate(field, file, rule)
+	if err != nil {
+		return model.Media{}, err
+	}
+
+	return saveMedia(upload, file.Filename, prefix, origin, UserUUID)
+}
+
+// saveMedia puts a validated upload and its variants in storage under prefix and adds it to the
+// media library.
+func saveMedia(upload *fileHelper.Upload, filename string, prefix string, origin string, UserUUID string) (model.Media, error) {
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	key, err := fileHelper.Key(prefix, fileHelper.GenerateUniqueFilename(name+upload.Ext))
+	if err != nil {
+		return model.Media{}, err
+	}
+
+	storage := fileHelper.GetStorage()
+	if err := storage.Put(key, bytes.NewReader(upload.Data), int64(len(upload.Data)), upload.ContentType); err != nil {
+		return model.Media{}, err
+	}
+
+	variants, err := fileHelper.MakeVariants(storage, key, upload.Data, upload.ContentType)
+	if err != nil {
+		return model.Media{}, err
+	}
+
+	variants_json, err := json.Marshal(variants)
+	if err != nil {
+		return model.Media{}, err
+	}
+
+	return repo.NewMediaRepo(database.GetDB()).Store(&model.StoreMedia{
+		UserUUID:    UserUUID,
+		StorageKey:  key,
+		URL:         storage.URL(key),
+		Filename:    filepath.Base(filename),
+		ContentType: upload.ContentType,
+		Size:        int64(len(upload.Data)),
+		Width:       upload.Width,
+		Height:      upload.Height,
+		Variants:    string(variants_json),
+		Origin:      origin,
+		IsPrivate:   fileHelper.IsPrivate(key),
+	})
+}
+
+// mediaPrefix is where library media goes in storage.
+func mediaPrefix(private bool) string {
+	if private {
+		return fileHelper.PrivatePrefix + "media"
+	}
+	return "media"
+}
+
+// linkMedia replaces the URLs of private media with signed links. The stored URLs don't work for
+// them, but stay as they are to keep track of the files.
+func linkMedia(media *model.Media) error {
+	if !media.IsPrivate {
+		return nil
+	}
+
+	storage := fileHelper.GetStorage()
+	link, err := fileHelper.Link(storage, media.StorageKey)
+	if err != nil {
+		return err
+	}
+	media.URL = link
+
+	if media.Variants != nil {
+		variants, err := fileHelper.LinkVariants(storage, *media.Variants)
+		if err != nil {
+			return err
+		}
+		linked := jsonutil.JSONRaw(variants)
+		media.Variants = &linked
+	}
+
+	return nil
+}
+
+// deleteMediaFiles removes the file of a deleted media row and its variants from storage.
+func deleteMediaFiles(media model.Media) error {
+	var variants []byte
+	if media.Variants != nil {
+		variants = *media.Variants
+	}
+	return fileHelper.DeleteWithVariants(fileHelper.GetStorage(), media.StorageKey, variants)
+}
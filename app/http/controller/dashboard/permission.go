@@ -8,6 +8,7 @@ import (
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/paginate"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -76,13 +77,14 @@ func PermissionShow(c *fiber.Ctx) error {
 // @Param name formData string true "Name" default(Permission Name)
 // @Success 200 {object} response.PermissionResponse
 // @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Security ApiKeyAuth
 // @Router /api/v1/dashboard/permission [post]
 func PermissionStore(c *fiber.Ctx) error {
 	permission := &model.StorePermission{}
 
-	if err := c.BodyParser(permission); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, permission); err != nil {
+		return response.Invalid(c, err)
 	}
 
 	repository := repo.NewPermissionRepo(database.GetDB())
@@ -105,6 +107,7 @@ func PermissionStore(c *fiber.Ctx) error {
 // @Param name formData string true "Name" default(Permission Name Update)
 // @Success 200 {object} response.PermissionResponse
 // @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Security ApiKeyAuth
 // @Router /api/v1/dashboard/permission/{id} [put]
 func PermissionUpdate(c *fiber.Ctx) error {
@@ -112,8 +115,8 @@ func PermissionUpdate(c *fiber.Ctx) error {
 
 	permission := &model.UpdatePermission{}
 
-	if err := c.BodyParser(permission); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, permission); err != nil {
+		return response.Invalid(c, err)
 	}
 
 	repository := repo.NewPermissionRepo(database.GetDB())
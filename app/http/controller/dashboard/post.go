@@ -3,16 +3,20 @@ package dashboard
 import (
 	"database/sql"
 	"errors"
-	"log"
-	"os"
-	"path/filepath"
+	"time"
 
 	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
 	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
 	fileHelper "github.com/arif-x/sqlx-mysql-boilerplate/pkg/file"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/logger"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/markup"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/paginate"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/related"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/sitemap"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -25,7 +29,8 @@ import (
 // @Param page query integer false "Page"
 // @Param limit query integer false "Limit"
 // @Param search query string false "Search"
-// @Param sort_by query string false "Sort By" Enums(posts.id, title, content)
+// @Param search_mode query string false "Search Mode" Enums(natural, boolean)
+// @Param sort_by query string false "Sort By" Enums(posts.id, title, content, view_count, relevance)
 // @Param sort query string false "Sort" Enums(ASC, DESC)
 // @Success 200 {object} response.PostResponse
 // @Failure 400,401,403 {object} response.ErrorResponse "Error"
@@ -36,7 +41,7 @@ func PostIndex(c *fiber.Ctx) error {
 
 	repository := repo.NewPostRepo(database.GetDB())
 
-	posts, count, err := repository.Index(limit, uint(limit*(page-1)), search, sort_by, sort)
+	posts, count, err := repository.Index(limit, uint(limit*(page-1)), search, c.Query("search_mode"), sort_by, sort)
 
 	if err != nil {
 		return response.InternalServerError(c, err)
@@ -80,22 +85,26 @@ func PostShow(c *fiber.Ctx) error {
 // @Accept multipart/form-data
 // @Produce json
 // @Param user_uuid formData string true "User UUID" default(87c76e22-e2f0-4ebf-bda8-56802c0a0577)
-// @Param tag_uuid formData string true "Post Tag UUID" default(22863142-1cfe-48cc-9640-ea88926429a4)
+// @Param tag_uuids formData []string true "Post Tag UUIDs" collectionFormat(multi)
+// @Param category_uuid formData string false "Primary Category UUID"
 // @Param title formData string true "Title" default(Title)
-// @Param thumbnail formData file true "Thumbnail"
+// @Param thumbnail formData file false "Thumbnail, a JPEG, PNG, GIF or SVG image, added to the media library"
+// @Param thumbnail_media_uuid formData string false "Media UUID of the thumbnail, when not uploading one"
 // @Param content formData string true "Content" default(Content)
+// @Param content_format formData string false "Content Format" Enums(markdown, html, plain) default(html)
 // @Param keyword formData string true "Keyword" default(keyword 1, keyword 2)
-// @Param is_active formData bool true "Is Active"
 // @Param is_highlight formData bool true "Is Highlight"
 // @Success 200 {object} response.PostResponse
 // @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Failure 413,415 {object} response.UploadErrorResponse "Rejected thumbnail"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Security ApiKeyAuth
 // @Router /api/v1/dashboard/post [post]
 func PostStore(c *fiber.Ctx) error {
 	post := &model.StorePost{}
 
-	if err := c.BodyParser(post); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, post); err != nil {
+		return response.Invalid(c, err)
 	}
 
 	form, err := c.MultipartForm()
@@ -105,39 +114,33 @@ func PostStore(c *fiber.Ctx) error {
 
 	thumbnail := form.File["thumbnail"]
 
-	thumbnail_data := ""
-
-	allowedExtensions := map[string]struct{}{
-		".jpg":  {},
-		".jpeg": {},
-		".png":  {},
-		".gif":  {},
-		".svg":  {},
-	}
-
 	repository := repo.NewPostRepo(database.GetDB())
 
+	// An uploaded thumbnail goes to the media library and wins over thumbnail_media_uuid.
 	for _, file := range thumbnail {
-		ext := filepath.Ext(file.Filename)
-		if _, allowed := allowedExtensions[ext]; !allowed {
-			return response.InternalServerError(c, errors.New("Unsupported file extension"))
-		}
-		filename := fileHelper.GenerateUniqueFilename(file.Filename)
-		if err := fileHelper.SaveFile(file, "./upload/post/", filename); err != nil {
-			return response.InternalServerError(c, errors.New("Can't Upload File"))
+		media, err := storeMedia(file, "thumbnail", fileHelper.ThumbnailRule(), "post", model.MediaOriginThumbnail, actorID(c))
+		if err != nil {
+			return uploadRejected(c, err)
 		}
-		thumbnail_data = os.Getenv("APP_FULL_URL") + "/upload/post/" + filename
+		post.ThumbnailMediaUUID = media.UUID.String()
 	}
 
-	post.Thumbnail = thumbnail_data
 	post.Slug = repository.GetSlug(post.Title, nil)
+	post.EditorUUID = actorID(c)
 
 	res, err := repository.Store(post)
 
 	if err != nil {
-		return response.InternalServerError(c, err)
+		if err == repo.ErrTagNotFound || err == repo.ErrCategoryNotFound || err == repo.ErrMediaNotFound || err == repo.ErrMediaPrivate || err == markup.ErrInvalidFormat {
+			return response.BadRequest(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
 	}
 
+	sitemap.Invalidate()
+	related.Invalidate()
+
 	return response.Store(c, res)
 }
 
@@ -149,15 +152,21 @@ func PostStore(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path string true "Post ID" default(f72cb686-2fc3-4147-8183-f93684780765)
 // @Param user_uuid formData string true "User UUID" default(87c76e22-e2f0-4ebf-bda8-56802c0a0577)
-// @Param tag_uuid formData string true "Post Tag UUID" default(22863142-1cfe-48cc-9640-ea88926429a4)
+// @Param tag_uuids formData []string true "Post Tag UUIDs" collectionFormat(multi)
+// @Param category_uuid formData string false "Primary Category UUID"
 // @Param title formData string true "Title" default(Title Update)
-// @Param thumbnail formData file false "Thumbnail"
+// @Param thumbnail formData file false "Thumbnail, a JPEG, PNG, GIF or SVG image, added to the media library"
+// @Param thumbnail_media_uuid formData string false "Media UUID of the thumbnail, unchanged when empty"
 // @Param content formData string true "Content" default(Content Update)
+// @Param content_format formData string false "Content Format, unchanged when empty" Enums(markdown, html, plain)
 // @Param keyword formData string true "Keyword" default(keyword 1, keyword 2)
-// @Param is_active formData bool true "Is Active"
+// @Param slug formData string false "Slug, regenerated from the title when empty unless locked"
+// @Param slug_locked formData bool false "Keep the slug when the title changes"
 // @Param is_highlight formData bool true "Is Highlight"
 // @Success 200 {object} response.PostResponse
 // @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Failure 413,415 {object} response.UploadErrorResponse "Rejected thumbnail"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Security ApiKeyAuth
 // @Router /api/v1/dashboard/post/{id} [put]
 func PostUpdate(c *fiber.Ctx) error {
@@ -165,8 +174,8 @@ func PostUpdate(c *fiber.Ctx) error {
 
 	post := &model.UpdatePost{}
 
-	if err := c.BodyParser(post); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, post); err != nil {
+		return response.Invalid(c, err)
 	}
 
 	form, err := c.MultipartForm()
@@ -176,46 +185,135 @@ func PostUpdate(c *fiber.Ctx) error {
 
 	thumbnail := form.File["thumbnail"]
 
-	thumbnail_data := ""
+	repository := repo.NewPostRepo(database.GetDB())
 
-	allowedExtensions := map[string]struct{}{
-		".jpg":  {},
-		".jpeg": {},
-		".png":  {},
-		".gif":  {},
-		".svg":  {},
+	current, err := repository.Show(ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
 	}
 
-	repository := repo.NewPostRepo(database.GetDB())
+	if post.Slug != "" {
+		if err := repository.CheckSlug(post.Slug, ID); err != nil {
+			if err == repo.ErrInvalidSlug || err == repo.ErrSlugTaken {
+				return response.BadRequest(c, err)
+			} else {
+				return response.InternalServerError(c, err)
+			}
+		}
+	} else if slugLocked(post.SlugLocked, current.SlugLocked) {
+		post.Slug = current.Slug
+	} else {
+		post.Slug = repository.GetSlug(post.Title, &ID)
+	}
 
+	// An uploaded thumbnail goes to the media library and wins over thumbnail_media_uuid.
 	for _, file := range thumbnail {
-		ext := filepath.Ext(file.Filename)
-		if _, allowed := allowedExtensions[ext]; !allowed {
-			return response.InternalServerError(c, errors.New("Unsupported file extension"))
+		media, err := storeMedia(file, "thumbnail", fileHelper.ThumbnailRule(), "post", model.MediaOriginThumbnail, actorID(c))
+		if err != nil {
+			return uploadRejected(c, err)
 		}
-		filename := fileHelper.GenerateUniqueFilename(file.Filename)
-		if err := fileHelper.SaveFile(file, "./upload/post/", filename); err != nil {
-			return response.InternalServerError(c, errors.New("Can't Upload File"))
+		post.ThumbnailMediaUUID = media.UUID.String()
+	}
+
+	post.EditorUUID = actorID(c)
+
+	res, err := repository.Update(ID, post)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else if err == repo.ErrTagNotFound || err == repo.ErrCategoryNotFound || err == repo.ErrMediaNotFound || err == repo.ErrMediaPrivate || err == markup.ErrInvalidFormat {
+			return response.BadRequest(c, err)
+		} else {
+			return response.InternalServerError(c, err)
 		}
-		if filename != "" {
-			thumbnail_data = os.Getenv("APP_FULL_URL") + "/upload/post/" + filename
+	}
+
+	if err := repo.NewPostRevisionRepo(database.GetDB()).Prune(ID, config.AppCfg().PostRevisionLimit); err != nil {
+		logger.Request(c).WithError(err).Error("failed pruning post revisions")
+	}
+
+	sitemap.Invalidate()
+	related.Invalidate()
+
+	return response.Update(c, res)
+}
+
+// PostStatus func change post status.
+// @Description Move a post through the draft, in_review, scheduled, published and archived workflow.
+// @Summary Change post status
+// @Tags Post
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Post ID" default(f72cb686-2fc3-4147-8183-f93684780765)
+// @Param status formData string true "Status" Enums(draft, in_review, scheduled, published, archived)
+// @Param published_at formData string false "Published At (RFC3339), required when scheduling" default(2030-01-01T08:00:00+07:00)
+// @Success 200 {object} response.PostResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/post/{id}/status [put]
+func PostStatus(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	request := &model.UpdatePostStatus{}
+
+	if err := validation.ParseBody(c, request); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	var published_at *time.Time
+	if request.PublishedAt != "" {
+		parsed, err := time.Parse(time.RFC3339, request.PublishedAt)
+		if err != nil {
+			return response.BadRequest(c, err)
 		}
+		published_at = &parsed
 	}
 
-	post.Thumbnail = thumbnail_data
-	post.Slug = repository.GetSlug(post.Title, &ID)
+	repository := repo.NewPostRepo(database.GetDB())
 
-	res, err := repository.Update(ID, post)
+	post, err := repository.Show(ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	permission, err := repo.PostTransitionPermission(post.Status, request.Status)
+	if err != nil {
+		return response.BadRequest(c, err)
+	}
+
+	actor, err := actorPermissions(c)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+	if !isSubset([]string{permission}, actor) {
+		return response.Forbidden(c, errors.New("You dont have permission to move this post to "+request.Status+"!"))
+	}
+
+	res, err := repository.UpdateStatus(ID, request.Status, published_at)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return response.NotFound(c, err)
+		} else if err == repo.ErrInvalidPublishedAt {
+			return response.BadRequest(c, err)
 		} else {
-			log.Println(err)
 			return response.InternalServerError(c, err)
 		}
 	}
 
+	sitemap.Invalidate()
+	related.Invalidate()
+
 	return response.Update(c, res)
 }
 
@@ -244,5 +342,24 @@ func PostDestroy(c *fiber.Ctx) error {
 		}
 	}
 
+	sitemap.Invalidate()
+	related.Invalidate()
+
 	return response.Destroy(c, res)
 }
+
+// slugLocked reports whether an update keeps the current slug. The requested lock wins over the stored one.
+func slugLocked(requested *bool, locked bool) bool {
+	if requested != nil {
+		return *requested
+	}
+	return locked
+}
+
+// uploadRejected answers a failed upload validation with its status and field, anything else with a 500.
+func uploadRejected(c *fiber.Ctx, err error) error {
+	if uploadErr, ok := err.(*fileHelper.UploadError); ok {
+		return response.UploadRejected(c, uploadErr.Status, uploadErr.Field, uploadErr.Message)
+	}
+	return response.InternalServerError(c, err)
+}
@@ -0,0 +1,136 @@
+package dashboard
+
+import (
+	"database/sql"
+
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/logger"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/related"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/sitemap"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PostRevisionIndex func gets all revisions of a post.
+// @Description Get all revisions of a post, newest first.
+// @Summary Get all post revisions
+// @Tags Post Revision
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID" default(f72cb686-2fc3-4147-8183-f93684780765)
+// @Success 200 {object} response.PostRevisionsResponse
+// @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/post/{id}/revision [get]
+func PostRevisionIndex(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	repository := repo.NewPostRevisionRepo(database.GetDB())
+	revisions, err := repository.Index(ID)
+
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	return response.Show(c, revisions)
+}
+
+// PostRevisionShow func gets single post revision.
+// @Description Get single post revision.
+// @Summary Get single post revision
+// @Tags Post Revision
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID" default(f72cb686-2fc3-4147-8183-f93684780765)
+// @Param revision path string true "Revision ID"
+// @Success 200 {object} response.PostRevisionResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/post/{id}/revision/{revision} [get]
+func PostRevisionShow(c *fiber.Ctx) error {
+	ID := c.Params("id")
+	revision_id := c.Params("revision")
+
+	repository := repo.NewPostRevisionRepo(database.GetDB())
+	revision, err := repository.Show(ID, revision_id)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Show(c, revision)
+}
+
+// PostRevisionDiff func compares a post revision.
+// @Description Get a unified diff per field between a revision and another revision or the current post.
+// @Summary Diff post revision
+// @Tags Post Revision
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID" default(f72cb686-2fc3-4147-8183-f93684780765)
+// @Param revision path string true "Revision ID"
+// @Param compare query string false "Revision ID to compare with, defaults to the current post" default(current)
+// @Success 200 {object} response.PostRevisionDiffResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/post/{id}/revision/{revision}/diff [get]
+func PostRevisionDiff(c *fiber.Ctx) error {
+	ID := c.Params("id")
+	revision_id := c.Params("revision")
+
+	repository := repo.NewPostRevisionRepo(database.GetDB())
+	diff, err := repository.Diff(ID, revision_id, c.Query("compare"))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Show(c, diff)
+}
+
+// PostRevisionRestore func restores a post revision.
+// @Description Restore a post to a revision. The restore is recorded as a new revision.
+// @Summary Restore post revision
+// @Tags Post Revision
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID" default(f72cb686-2fc3-4147-8183-f93684780765)
+// @Param revision path string true "Revision ID"
+// @Success 200 {object} response.PostResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/post/{id}/revision/{revision}/restore [post]
+func PostRevisionRestore(c *fiber.Ctx) error {
+	ID := c.Params("id")
+	revision_id := c.Params("revision")
+
+	repository := repo.NewPostRevisionRepo(database.GetDB())
+	res, err := repository.Restore(ID, revision_id, actorID(c))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	if err := repository.Prune(ID, config.AppCfg().PostRevisionLimit); err != nil {
+		logger.Request(c).WithError(err).Error("failed pruning post revisions")
+	}
+
+	sitemap.Invalidate()
+	related.Invalidate()
+
+	return response.Update(c, res)
+}
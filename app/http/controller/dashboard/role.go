@@ -8,6 +8,7 @@ import (
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/paginate"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -74,16 +75,18 @@ func RoleShow(c *fiber.Ctx) error {
 // @Accept multipart/form-data
 // @Produce json
 // @Param name formData string true "Name" default(Role Name)
+// @Param code formData string false "Stable Role Key" default(role-name)
 // @Param is_active formData bool true "Is Active"
 // @Success 200 {object} response.RoleResponse
 // @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Security ApiKeyAuth
 // @Router /api/v1/dashboard/role [post]
 func RoleStore(c *fiber.Ctx) error {
 	role := &model.StoreRole{}
 
-	if err := c.BodyParser(role); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, role); err != nil {
+		return response.Invalid(c, err)
 	}
 
 	repository := repo.NewRoleRepo(database.GetDB())
@@ -107,6 +110,7 @@ func RoleStore(c *fiber.Ctx) error {
 // @Param is_active formData bool true "Is Active"
 // @Success 200 {object} response.RoleResponse
 // @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Security ApiKeyAuth
 // @Router /api/v1/dashboard/role/{id} [put]
 func RoleUpdate(c *fiber.Ctx) error {
@@ -114,8 +118,16 @@ func RoleUpdate(c *fiber.Ctx) error {
 
 	role := &model.UpdateRole{}
 
-	if err := c.BodyParser(role); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, role); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	allowed, err := canAssignRole(c, ID)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+	if !allowed {
+		return response.Forbidden(c, errPrivilegeEscalation)
 	}
 
 	repository := repo.NewRoleRepo(database.GetDB())
@@ -124,6 +136,8 @@ func RoleUpdate(c *fiber.Ctx) error {
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return response.NotFound(c, err)
+		} else if err == repo.ErrSystemRole {
+			return response.Forbidden(c, err)
 		} else {
 			return response.InternalServerError(c, err)
 		}
@@ -146,12 +160,22 @@ func RoleUpdate(c *fiber.Ctx) error {
 func RoleDestroy(c *fiber.Ctx) error {
 	ID := c.Params("id")
 
+	allowed, err := canAssignRole(c, ID)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+	if !allowed {
+		return response.Forbidden(c, errPrivilegeEscalation)
+	}
+
 	repository := repo.NewRoleRepo(database.GetDB())
 	res, err := repository.Destroy(ID)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return response.NotFound(c, err)
+		} else if err == repo.ErrSystemRole {
+			return response.Forbidden(c, err)
 		} else {
 			return response.InternalServerError(c, err)
 		}
@@ -2,12 +2,12 @@ package dashboard
 
 import (
 	"database/sql"
-	"log"
 
 	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
 	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -49,14 +49,34 @@ func SyncPermissionShow(c *fiber.Ctx) error {
 // @Param permission_uuid formData []string true "permission_uuid" collectionFormat(multi)
 // @Success 200 {object} response.SyncPermissionResponse
 // @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Security ApiKeyAuth
 // @Router /api/v1/dashboard/sync-permission/{id} [put]
 func SyncPermissionUpdate(c *fiber.Ctx) error {
 	ID := c.Params("id")
 	req := &model.UpdateSyncPermission{}
 
-	if err := c.BodyParser(req); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, req); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	actor, err := actorPermissions(c)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	current, err := repo.NewRoleRepo(database.GetDB()).Permissions(ID)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	requested, err := repo.NewPermissionRepo(database.GetDB()).Names(req.PermissionUUID)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	if !isSubset(current, actor) || !isSubset(requested, actor) {
+		return response.Forbidden(c, errPrivilegeEscalation)
 	}
 
 	repository := repo.NewSyncPermissionRepo(database.GetDB())
@@ -66,7 +86,6 @@ func SyncPermissionUpdate(c *fiber.Ctx) error {
 		if err == sql.ErrNoRows {
 			return response.NotFound(c, err)
 		} else {
-			log.Println(err)
 			return response.InternalServerError(c, err)
 		}
 	}
@@ -2,13 +2,15 @@ package dashboard
 
 import (
 	"database/sql"
-	"fmt"
 
 	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
 	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/paginate"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/related"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/sitemap"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -78,13 +80,14 @@ func TagShow(c *fiber.Ctx) error {
 // @Param is_active formData bool true "Is Active"
 // @Success 200 {object} response.TagResponse
 // @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Security ApiKeyAuth
 // @Router /api/v1/dashboard/tags [post]
 func TagStore(c *fiber.Ctx) error {
 	tag := &model.StoreTag{}
 
-	if err := c.BodyParser(tag); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, tag); err != nil {
+		return response.Invalid(c, err)
 	}
 
 	repository := repo.NewTagRepo(database.GetDB())
@@ -93,12 +96,13 @@ func TagStore(c *fiber.Ctx) error {
 
 	res, err := repository.Store(tag)
 
-	fmt.Print(err)
-
 	if err != nil {
 		return response.InternalServerError(c, err)
 	}
 
+	sitemap.Invalidate()
+	related.Invalidate()
+
 	return response.Store(c, res)
 }
 
@@ -110,9 +114,12 @@ func TagStore(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path string true "Post Tag ID" default(22863142-1cfe-48cc-9640-ea88926429a4)
 // @Param name formData string true "Name" default(Tag Name Update)
+// @Param slug formData string false "Slug, regenerated from the name when empty unless locked"
+// @Param slug_locked formData bool false "Keep the slug when the name changes"
 // @Param is_active formData bool true "Is Active"
 // @Success 200 {object} response.TagResponse
 // @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Security ApiKeyAuth
 // @Router /api/v1/dashboard/tags/{id} [put]
 func TagUpdate(c *fiber.Ctx) error {
@@ -120,20 +127,48 @@ func TagUpdate(c *fiber.Ctx) error {
 
 	tag := &model.UpdateTag{}
 
-	if err := c.BodyParser(tag); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, tag); err != nil {
+		return response.Invalid(c, err)
 	}
 
 	repository := repo.NewTagRepo(database.GetDB())
 
-	tag.Slug = repository.GetSlug(tag.Name, &ID)
+	current, err := repository.Show(ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	if tag.Slug != "" {
+		if err := repository.CheckSlug(tag.Slug, ID); err != nil {
+			if err == repo.ErrInvalidSlug || err == repo.ErrSlugTaken {
+				return response.BadRequest(c, err)
+			} else {
+				return response.InternalServerError(c, err)
+			}
+		}
+	} else if slugLocked(tag.SlugLocked, current.SlugLocked) {
+		tag.Slug = current.Slug
+	} else {
+		tag.Slug = repository.GetSlug(tag.Name, &ID)
+	}
 
 	res, err := repository.Update(ID, tag)
 
 	if err != nil {
-		return response.InternalServerError(c, err)
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
 	}
 
+	sitemap.Invalidate()
+	related.Invalidate()
+
 	return response.Update(c, res)
 }
 
@@ -162,5 +197,8 @@ func TagDestroy(c *fiber.Ctx) error {
 		}
 	}
 
+	sitemap.Invalidate()
+	related.Invalidate()
+
 	return response.Destroy(c, res)
 }
@@ -0,0 +1,422 @@
+package dashboard
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	fileHelper "github.com/arif-x/sqlx-mysql-boilerplate/pkg/file"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/logger"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// The uploads below follow the tus 1.0.0 protocol with the creation, expiration, checksum and
+// termination extensions, see https://tus.io/protocols/resumable-upload. The checksum is the one
+// of the whole file, sent when the upload is created and verified once the last chunk is in.
+const (
+	tusVersion     = "1.0.0"
+	tusExtension   = "creation,expiration,checksum,termination"
+	tusChecksum    = "sha256"
+	tusContentType = "application/offset+octet-stream"
+)
+
+var errUploadOverflow = errors.New("The chunk goes past the length of the upload")
+
+// UploadOptions func describes the upload protocol.
+// @Description Get the supported tus versions, extensions and the largest upload allowed.
+// @Summary Describe resumable uploads
+// @Tags Upload
+// @Success 204
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/uploads [options]
+func UploadOptions(c *fiber.Ctx) error {
+	c.Set("Tus-Resumable", tusVersion)
+	c.Set("Tus-Version", tusVersion)
+	c.Set("Tus-Extension", tusExtension)
+	c.Set("Tus-Checksum-Algorithm", tusChecksum)
+	c.Set("Tus-Max-Size", strconv.FormatInt(config.StorageCfg().ResumableMaxSize, 10))
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// UploadStore func create resumable upload.
+// @Description Start a resumable upload. Chunks are then sent to the returned Location with PATCH.
+// @Summary Create resumable upload
+// @Tags Upload
+// @Produce json
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Param Upload-Length header integer true "Size of the whole file in bytes"
+// @Param Upload-Checksum header string true "sha256 and the base64 digest of the whole file"
+// @Param Upload-Metadata header string false "Comma separated keys and base64 values, filename and private are used"
+// @Success 201 {object} response.UploadResponse
+// @Failure 400,401,403,412 {object} response.ErrorResponse "Error"
+// @Failure 413 {object} response.UploadErrorResponse "Rejected file"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/uploads [post]
+func UploadStore(c *fiber.Ctx) error {
+	if !tusResumable(c) {
+		return nil
+	}
+
+	length, err := strconv.ParseInt(c.Get("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		return response.BadRequest(c, err)
+	}
+	cfg := config.StorageCfg()
+	if length > cfg.ResumableMaxSize {
+		return uploadRejected(c, fileHelper.TooLarge("file", cfg.ResumableMaxSize))
+	}
+
+	checksum := c.Get("Upload-Checksum")
+	algorithm, digest, _ := strings.Cut(checksum, " ")
+	if algorithm != tusChecksum {
+		return response.BadRequest(c, errors.New("unsupported checksum algorithm"))
+	}
+	if sum, err := base64.StdEncoding.DecodeString(digest); err != nil || len(sum) != sha256.Size {
+		return response.BadRequest(c, errors.New("invalid checksum"))
+	}
+
+	metadata := uploadMetadata(c.Get("Upload-Metadata"))
+	filename := metadata["filename"]
+	if filename == "" {
+		filename = metadata["name"]
+	}
+	if filename == "" {
+		filename = "upload"
+	}
+	private, _ := strconv.ParseBool(metadata["private"])
+
+	if err := os.MkdirAll(cfg.UploadTempDir, 0o755); err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	repository := repo.NewUploadRepo(database.GetDB())
+	upload, err := repository.Store(&model.StoreUpload{
+		UserUUID:  actorID(c),
+		Filename:  filepath.Base(filename),
+		Length:    length,
+		Checksum:  checksum,
+		IsPrivate: private,
+		ExpiresAt: time.Now().Add(cfg.UploadExpiry),
+	})
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	temp, err := os.Create(fileHelper.TempPath(upload.UUID.String()))
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+	temp.Close()
+
+	c.Location(strings.TrimSuffix(c.Path(), "/") + "/" + upload.UUID.String())
+	uploadHeaders(c, upload)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  true,
+		"message": "Created",
+		"data":    upload,
+	})
+}
+
+// UploadHead func gets resumable upload progress.
+// @Description Get how many bytes of a resumable upload were received, to resume from there.
+// @Summary Get resumable upload progress
+// @Tags Upload
+// @Param id path string true "Upload ID"
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Success 200
+// @Header 200 {integer} Upload-Offset "Bytes received"
+// @Header 200 {integer} Upload-Length "Size of the whole file"
+// @Failure 404,410,412
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/uploads/{id} [head]
+func UploadHead(c *fiber.Ctx) error {
+	if !tusResumable(c) {
+		return nil
+	}
+
+	repository := repo.NewUploadRepo(database.GetDB())
+	upload, err := repository.Show(c.Params("id"), actorID(c))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.SendStatus(fiber.StatusNotFound)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+	if upload.MediaUUID == nil && upload.ExpiresAt.Before(time.Now()) {
+		return c.SendStatus(fiber.StatusGone)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	uploadHeaders(c, upload)
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// UploadShow func gets single resumable upload.
+// @Description Get single resumable upload, with the media it became once complete.
+// @Summary Get single resumable upload
+// @Tags Upload
+// @Accept json
+// @Produce json
+// @Param id path string true "Upload ID"
+// @Success 200 {object} response.UploadResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/uploads/{id} [get]
+func UploadShow(c *fiber.Ctx) error {
+	repository := repo.NewUploadRepo(database.GetDB())
+	upload, err := repository.Show(c.Params("id"), actorID(c))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Show(c, upload)
+}
+
+// UploadPatch func send resumable upload chunk.
+// @Description Send the next chunk of a resumable upload, starting at Upload-Offset. Once the last
+// @Description chunk is in, the file is checked against its checksum, validated and added to the
+// @Description media library, and the upload is returned with its media_uuid, to use as a post
+// @Description thumbnail_media_uuid. Chunks must fit in the request body limit.
+// @Summary Send resumable upload chunk
+// @Tags Upload
+// @Accept application/offset+octet-stream
+// @Produce json
+// @Param id path string true "Upload ID"
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Param Upload-Offset header integer true "Offset of the chunk, the bytes received so far"
+// @Success 200 {object} response.UploadResponse "Upload complete"
+// @Success 204 "Chunk received"
+// @Failure 400,401,403,404,409,410,412 {object} response.ErrorResponse "Error"
+// @Failure 413,415,460 {object} response.UploadErrorResponse "Rejected file"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/uploads/{id} [patch]
+func UploadPatch(c *fiber.Ctx) error {
+	if !tusResumable(c) {
+		return nil
+	}
+
+	if c.Get(fiber.HeaderContentType) != tusContentType {
+		return response.UploadRejected(c, fiber.StatusUnsupportedMediaType, "file", "Chunks must be sent as "+tusContentType)
+	}
+
+	offset, err := strconv.ParseInt(c.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		return response.BadRequest(c, err)
+	}
+
+	ID := c.Params("id")
+	body := c.Body()
+
+	repository := repo.NewUploadRepo(database.GetDB())
+	upload, claimed, err := repository.Append(ID, actorID(c), offset, func(upload model.Upload) (int64, error) {
+		return writeChunk(upload, body)
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else if err == repo.ErrUploadOffset {
+			return response.Conflict(c, err)
+		} else if err == repo.ErrUploadExpired {
+			return response.Gone(c, err)
+		} else if err == errUploadOverflow {
+			return response.UploadRejected(c, fiber.StatusRequestEntityTooLarge, "file", err.Error())
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	uploadHeaders(c, upload)
+
+	if upload.Offset < upload.Length {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	if upload.MediaUUID != nil {
+		return response.Show(c, upload)
+	}
+	// A retried last chunk while another request is finishing the upload.
+	if !claimed {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	upload, err = finishUpload(c, upload)
+	if err != nil {
+		// A file that fails its checksum or validation can't be resumed, so it is dropped. Otherwise
+		// a retry may finish it.
+		if _, ok := err.(*fileHelper.UploadError); ok {
+			if _, err := repository.Destroy(ID, upload.UserUUID); err != nil {
+				return response.InternalServerError(c, err)
+			}
+			_ = fileHelper.RemoveTemp(ID)
+		} else if err := repository.Release(ID); err != nil {
+			return response.InternalServerError(c, err)
+		}
+		return uploadRejected(c, err)
+	}
+
+	return response.Show(c, upload)
+}
+
+// UploadDestroy func cancel resumable upload.
+// @Description Cancel a resumable upload and drop what was received. Media it became is kept.
+// @Summary Cancel resumable upload
+// @Tags Upload
+// @Param id path string true "Upload ID"
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Success 204
+// @Failure 401,403,404,412 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/uploads/{id} [delete]
+func UploadDestroy(c *fiber.Ctx) error {
+	if !tusResumable(c) {
+		return nil
+	}
+
+	ID := c.Params("id")
+
+	repository := repo.NewUploadRepo(database.GetDB())
+	if _, err := repository.Destroy(ID, actorID(c)); err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	if err := fileHelper.RemoveTemp(ID); err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	c.Set("Tus-Resumable", tusVersion)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// tusResumable checks the client speaks the supported protocol version, sending 412 otherwise.
+func tusResumable(c *fiber.Ctx) bool {
+	if c.Get("Tus-Resumable") == tusVersion {
+		return true
+	}
+
+	c.Set("Tus-Version", tusVersion)
+	_ = response.Fail(c, response.NewError(fiber.StatusPreconditionFailed, response.CodePreconditionFailed, "Unsupported Tus-Resumable version, use "+tusVersion))
+	return false
+}
+
+func uploadHeaders(c *fiber.Ctx, upload model.Upload) {
+	c.Set("Tus-Resumable", tusVersion)
+	c.Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	c.Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+}
+
+// uploadMetadata decodes an Upload-Metadata header, comma separated keys each followed by a
+// base64 value. Pairs that don't decode are left out.
+func uploadMetadata(header string) map[string]string {
+	metadata := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		metadata[key] = string(decoded)
+	}
+	return metadata
+}
+
+// writeChunk writes body at the offset of the upload, dropping whatever an interrupted request
+// left past it.
+func writeChunk(upload model.Upload, body []byte) (int64, error) {
+	if int64(len(body)) > upload.Length-upload.Offset {
+		return 0, errUploadOverflow
+	}
+
+	temp, err := os.OpenFile(fileHelper.TempPath(upload.UUID.String()), os.O_WRONLY, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer temp.Close()
+
+	if err := temp.Truncate(upload.Offset); err != nil {
+		return 0, err
+	}
+	written, err := temp.WriteAt(body, upload.Offset)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(written), temp.Close()
+}
+
+// finishUpload verifies a complete upload against its checksum, validates it and saves it as
+// media, then removes the temporary file.
+func finishUpload(c *fiber.Ctx, upload model.Upload) (model.Upload, error) {
+	ID := upload.UUID.String()
+
+	temp, err := os.Open(fileHelper.TempPath(ID))
+	if err != nil {
+		return upload, err
+	}
+	defer temp.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, temp); err != nil {
+		return upload, err
+	}
+	if upload.Checksum == nil || *upload.Checksum != tusChecksum+" "+base64.StdEncoding.EncodeToString(hash.Sum(nil)) {
+		return upload, &fileHelper.UploadError{Field: "file", Status: response.StatusChecksumMismatch, Message: "The file does not match its checksum"}
+	}
+
+	if _, err := temp.Seek(0, io.SeekStart); err != nil {
+		return upload, err
+	}
+	validated, err := fileHelper.ValidateReader("file", temp, upload.Length, fileHelper.ResumableRule())
+	if err != nil {
+		return upload, err
+	}
+
+	media, err := saveMedia(validated, upload.Filename, mediaPrefix(upload.IsPrivate), model.MediaOriginLibrary, upload.UserUUID)
+	if err != nil {
+		return upload, err
+	}
+
+	completed, err := repo.NewUploadRepo(database.GetDB()).Complete(ID, media.UUID.String())
+	if err != nil {
+		return upload, err
+	}
+
+	// The media is saved by now, a temporary file left behind is removed by media:prune.
+	temp.Close()
+	if err := fileHelper.RemoveTemp(ID); err != nil {
+		logger.Request(c).WithError(err).Error("failed removing temporary upload")
+	}
+
+	return completed, nil
+}
@@ -2,14 +2,23 @@ package dashboard
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 
 	hash "github.com/arif-x/sqlx-mysql-boilerplate/pkg/hash"
 
+	authController "github.com/arif-x/sqlx-mysql-boilerplate/app/http/controller/auth"
+	authRepo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/auth"
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
 	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
 	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/paginate"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -82,13 +91,22 @@ func UserShow(c *fiber.Ctx) error {
 // @Param role_uuid formData string true "Role ID" default(22863142-1cfe-48cc-9640-ea88926429a4)
 // @Success 200 {object} response.UserResponse
 // @Failure 400,401,403 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Security ApiKeyAuth
 // @Router /api/v1/dashboard/user [post]
 func UserStore(c *fiber.Ctx) error {
 	user := &model.StoreUser{}
 
-	if err := c.BodyParser(user); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, user); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	allowed, err := canAssignRole(c, user.RoleUUID)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+	if !allowed {
+		return response.Forbidden(c, errPrivilegeEscalation)
 	}
 
 	password, err := hash.Hash([]byte(user.Password))
@@ -122,6 +140,7 @@ func UserStore(c *fiber.Ctx) error {
 // @Param role_uuid formData string true "Role ID" default(22863142-1cfe-48cc-9640-ea88926429a4)
 // @Success 200 {object} response.UserResponse
 // @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
 // @Security ApiKeyAuth
 // @Router /api/v1/dashboard/user/{id} [put]
 func UserUpdate(c *fiber.Ctx) error {
@@ -129,8 +148,20 @@ func UserUpdate(c *fiber.Ctx) error {
 
 	user := &model.UpdateUser{}
 
-	if err := c.BodyParser(user); err != nil {
-		return response.BadRequest(c, err)
+	if err := validation.ParseBody(c, user); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	manageable, err := canManageUser(c, ID)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+	allowed, err := canAssignRole(c, user.RoleUUID)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+	if !manageable || !allowed {
+		return response.Forbidden(c, errPrivilegeEscalation)
 	}
 
 	if user.Password != "" {
@@ -170,6 +201,14 @@ func UserUpdate(c *fiber.Ctx) error {
 func UserDestroy(c *fiber.Ctx) error {
 	ID := c.Params("id")
 
+	manageable, err := canManageUser(c, ID)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+	if !manageable {
+		return response.Forbidden(c, errPrivilegeEscalation)
+	}
+
 	repository := repo.NewUserRepo(database.GetDB())
 	res, err := repository.Destroy(ID)
 
@@ -183,3 +222,69 @@ func UserDestroy(c *fiber.Ctx) error {
 
 	return response.Destroy(c, res)
 }
+
+// UserImpersonate func impersonate user.
+// @Description Issue a short-lived token to see the dashboard as the given user.
+// @Summary Impersonate user
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" default(f72cb686-2fc3-4147-8183-f93684780765)
+// @Success 200 {object} response.AuthWithPermissionResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Security ApiKeyAuth
+// @Router /api/v1/dashboard/user/{id}/impersonate [post]
+func UserImpersonate(c *fiber.Ctx) error {
+	ID := c.Params("id")
+
+	user := c.Locals("user").(*JWTTokenAuthed.Token)
+	claims := user.Claims.(JWTTokenAuthed.MapClaims)
+	user_id, _ := claims["user_id"].(string)
+
+	if impersonator_id, ok := claims["impersonator_id"].(string); ok && impersonator_id != "" {
+		return response.Forbidden(c, errors.New("You can't impersonate while impersonating!"))
+	}
+
+	if ID == user_id {
+		return response.BadRequest(c, errors.New("You can't impersonate yourself!"))
+	}
+
+	manageable, err := canManageUser(c, ID)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+	if !manageable {
+		return response.Forbidden(c, errPrivilegeEscalation)
+	}
+
+	repository := authRepo.NewAuthRepo(database.GetDB())
+	target, role_name, permission, err := repository.ByUUID(ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	impersonation_id := uuid.New().String()
+	token, expires, err := authController.GenerateImpersonationToken(target.UUID, target.Username, target.Email, target.Name, target.IsActive, target.EmailVerifiedAt, target.RoleUUID, permission, user_id, impersonation_id)
+	if err != nil {
+		return response.InternalServerError(c, errors.New("Internal Error"))
+	}
+
+	if err := repository.StartImpersonation(impersonation_id, target.UUID.String(), user_id, expires); err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":           true,
+		"message":          fmt.Sprintf("Impersonating %s, token will be expired within %d minutes", target.Username, config.AppCfg().ImpersonationExpireMinutesCount),
+		"data":             token,
+		"role_name":        role_name,
+		"permission":       permission,
+		"impersonator_id":  user_id,
+		"token_expired_at": expires,
+	})
+}
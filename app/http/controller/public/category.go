@@ -0,0 +1,69 @@
+package public
+
+import (
+	"database/sql"
+
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/public"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/paginate"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PublicCategoryTree func gets the category tree.
+// @Description Get active categories nested under their parents.
+// @Summary Get category tree
+// @Tags Public Category
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.PublicCategoryTreeResponse
+// @Failure 400,403,404 {object} response.ErrorResponse "Error"
+// @Router /api/v1/public/category/tree [get]
+func CategoryTree(c *fiber.Ctx) error {
+	repository := repo.NewCategoryRepo(database.GetDB())
+
+	categories, err := repository.Tree()
+
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	return response.Show(c, categories)
+}
+
+// PublicPostByCategory func gets post by category.
+// @Description Get post by category, optionally including its descendant categories.
+// @Summary Get post by category
+// @Tags Public Post
+// @Accept json
+// @Produce json
+// @Param slug path string true "Category Slug" default(category-1)
+// @Param include_descendants query bool false "Include posts of descendant categories"
+// @Param page query integer false "Page"
+// @Param limit query integer false "Limit"
+// @Param sort_by query string false "Sort By" Enums(posts.id, title, published_at)
+// @Param sort query string false "Sort" Enums(ASC, DESC)
+// @Success 200 {object} response.PublicPostsByCategoryResponse
+// @Failure 400,403,404 {object} response.ErrorResponse "Error"
+// @Router /api/v1/public/post/category/{slug} [get]
+func CategoryPost(c *fiber.Ctx) error {
+	page, limit, _, sort_by, sort := paginate.Paginate(c)
+	slug := c.Params("slug")
+	repository := repo.NewCategoryRepo(database.GetDB())
+
+	if sort_by == "id" {
+		sort_by = "posts.id"
+	}
+
+	posts, count, err := repository.CategoryPost(slug, c.QueryBool("include_descendants"), limit, uint(limit*(page-1)), sort_by, sort)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Index(c, page, limit, count, posts)
+}
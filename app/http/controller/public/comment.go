@@ -0,0 +1,118 @@
+package public
+
+import (
+	"database/sql"
+	"errors"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/public"
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/public"
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/paginate"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/related"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/validation"
+	"github.com/gofiber/fiber/v2"
+	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
+)
+
+// PublicPostComments func gets post comments.
+// @Description Get the approved comments of a post, each with its approved replies.
+// @Summary Get post comments
+// @Tags Public Comment
+// @Accept json
+// @Produce json
+// @Param slug path string true "Post Slug" default(title-1-1)
+// @Param page query integer false "Page"
+// @Param limit query integer false "Limit"
+// @Param sort query string false "Sort" Enums(ASC, DESC)
+// @Success 200 {object} response.PublicCommentsResponse
+// @Failure 400,403,404 {object} response.ErrorResponse "Error"
+// @Router /api/v1/public/post/{slug}/comments [get]
+func PostComments(c *fiber.Ctx) error {
+	page, limit, _, _, sort := paginate.Paginate(c)
+	slug := c.Params("slug")
+
+	repository := repo.NewCommentRepo(database.GetDB())
+	comments, count, err := repository.Index(slug, limit, uint(limit*(page-1)), sort)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.IndexWithNextPage(c, page, limit, count, comments)
+}
+
+// PublicPostCommentStore func create post comment.
+// @Description Comment on a post or reply to a comment. Guests must send a name and email when guest comments are allowed.
+// @Summary Create post comment
+// @Tags Public Comment
+// @Accept multipart/form-data
+// @Produce json
+// @Param slug path string true "Post Slug" default(title-1-1)
+// @Param parent_uuid formData string false "Parent Comment UUID"
+// @Param name formData string false "Guest Name"
+// @Param email formData string false "Guest Email"
+// @Param content formData string true "Content"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.PublicCommentResponse
+// @Failure 400,401,403,404 {object} response.ErrorResponse "Error"
+// @Failure 422 {object} response.ValidationErrorResponse "Invalid fields"
+// @Router /api/v1/public/post/{slug}/comments [post]
+func PostCommentStore(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+
+	comment := &model.StoreComment{}
+
+	if err := validation.ParseBody(c, comment); err != nil {
+		return response.Invalid(c, err)
+	}
+
+	if user, ok := c.Locals("user").(*JWTTokenAuthed.Token); ok {
+		claims := user.Claims.(JWTTokenAuthed.MapClaims)
+		comment.UserUUID, _ = claims["user_id"].(string)
+	} else {
+		if !config.AppCfg().CommentAllowGuest {
+			return response.Forbidden(c, errors.New("Please log in to comment!"))
+		}
+		// Guests have to say who they are.
+		errs := validation.Errors{}
+		if comment.Name == "" {
+			errs.Add("name", "The name field is required")
+		}
+		if comment.Email == "" {
+			errs.Add("email", "The email field is required")
+		}
+		if len(errs) > 0 {
+			return response.Invalid(c, errs)
+		}
+	}
+
+	comment.Status = "pending"
+	if config.AppCfg().CommentAutoApprove {
+		comment.Status = "approved"
+	}
+
+	repository := repo.NewCommentRepo(database.GetDB())
+	res, err := repository.Store(slug, comment)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else if err == repo.ErrCommentParentNotFound {
+			return response.BadRequest(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	if comment.Status == "approved" {
+		related.Invalidate()
+	}
+
+	return response.Store(c, res)
+}
@@ -0,0 +1,258 @@
+package public
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/public"
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/public"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	fileHelper "github.com/arif-x/sqlx-mysql-boilerplate/pkg/file"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gorilla/feeds"
+)
+
+// Feed formats accepted by TagFeed and UserFeed.
+const (
+	FeedRSS  = "rss"
+	FeedAtom = "atom"
+	FeedJSON = "json"
+)
+
+const feedLimit = 20
+
+// RSSFeed func gets the RSS feed.
+// @Description Get the latest published posts as RSS 2.0.
+// @Summary Get RSS feed
+// @Tags Feed
+// @Produce xml
+// @Success 200 {string} string "RSS 2.0"
+// @Success 304 {string} string "Not Modified"
+// @Router /feed.xml [get]
+func RSSFeed(c *fiber.Ctx) error {
+	return renderFeed(c, FeedRSS, "", "")
+}
+
+// AtomFeed func gets the Atom feed.
+// @Description Get the latest published posts as Atom.
+// @Summary Get Atom feed
+// @Tags Feed
+// @Produce xml
+// @Success 200 {string} string "Atom"
+// @Success 304 {string} string "Not Modified"
+// @Router /atom.xml [get]
+func AtomFeed(c *fiber.Ctx) error {
+	return renderFeed(c, FeedAtom, "", "")
+}
+
+// JSONFeed func gets the JSON feed.
+// @Description Get the latest published posts as JSON Feed.
+// @Summary Get JSON feed
+// @Tags Feed
+// @Produce json
+// @Success 200 {string} string "JSON Feed"
+// @Success 304 {string} string "Not Modified"
+// @Router /feed.json [get]
+func JSONFeed(c *fiber.Ctx) error {
+	return renderFeed(c, FeedJSON, "", "")
+}
+
+// TagFeed func gets the feed of a tag.
+// @Description Get the latest published posts of a tag as RSS 2.0, Atom or JSON Feed.
+// @Summary Get tag feed
+// @Tags Feed
+// @Produce xml,json
+// @Param slug path string true "Tag Slug" default(tag-1)
+// @Success 200 {string} string "Feed"
+// @Success 304 {string} string "Not Modified"
+// @Failure 404 {object} response.ErrorResponse "Error"
+// @Router /api/v1/public/post/tag/{slug}/feed.xml [get]
+// @Router /api/v1/public/post/tag/{slug}/atom.xml [get]
+// @Router /api/v1/public/post/tag/{slug}/feed.json [get]
+func TagFeed(format string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return renderFeed(c, format, c.Params("slug"), "")
+	}
+}
+
+// UserFeed func gets the feed of an author.
+// @Description Get the latest published posts of an author as RSS 2.0, Atom or JSON Feed.
+// @Summary Get author feed
+// @Tags Feed
+// @Produce xml,json
+// @Param username path string true "Username" default(superadmin)
+// @Success 200 {string} string "Feed"
+// @Success 304 {string} string "Not Modified"
+// @Failure 404 {object} response.ErrorResponse "Error"
+// @Router /api/v1/public/post/user/{username}/feed.xml [get]
+// @Router /api/v1/public/post/user/{username}/atom.xml [get]
+// @Router /api/v1/public/post/user/{username}/feed.json [get]
+func UserFeed(format string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return renderFeed(c, format, "", c.Params("username"))
+	}
+}
+
+func renderFeed(c *fiber.Ctx, format string, tag_slug string, username string) error {
+	repository := repo.NewPostRepo(database.GetDB())
+	data, err := repository.Feed(tag_slug, username, feedLimit)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	base := strings.TrimRight(os.Getenv("APP_FULL_URL"), "/")
+
+	feed := &feeds.Feed{
+		Title:       "Posts",
+		Link:        &feeds.Link{Href: base + "/api/v1/public/post"},
+		Description: "The latest published posts",
+	}
+	switch {
+	case tag_slug != "":
+		feed.Title = "Posts tagged " + data.Name
+		feed.Link = &feeds.Link{Href: base + "/api/v1/public/post/tag/" + tag_slug}
+		feed.Description = "The latest published posts tagged " + data.Name
+	case username != "":
+		feed.Title = "Posts by " + data.Name
+		feed.Link = &feeds.Link{Href: base + "/api/v1/public/post/user/" + username}
+		feed.Description = "The latest published posts by " + data.Name
+	}
+
+	hash := sha1.New()
+	hash.Write([]byte(format + c.Path()))
+
+	var last_modified time.Time
+	for _, post := range data.Posts {
+		updated := post.PublishedAt
+		if post.UpdatedAt != nil && post.UpdatedAt.After(updated) {
+			updated = *post.UpdatedAt
+		}
+		if updated.After(last_modified) {
+			last_modified = updated
+		}
+		hash.Write([]byte(post.UUID.String() + strconv.FormatInt(updated.UnixNano(), 10)))
+
+		// Posts saved before content rendering existed fall back to their source.
+		description, content := post.Excerpt, post.ContentHTML
+		if description == "" {
+			description = post.Keyword
+		}
+		if content == "" {
+			content = post.Content
+		}
+
+		link := base + "/api/v1/public/post/" + post.Slug
+		item := &feeds.Item{
+			Id:          link,
+			Title:       post.Title,
+			Link:        &feeds.Link{Href: link},
+			Author:      &feeds.Author{Name: post.AuthorName, Email: post.AuthorEmail},
+			Description: description,
+			Content:     content,
+			Created:     post.PublishedAt,
+			Updated:     updated,
+			Enclosure:   feedEnclosure(base, post),
+		}
+		feed.Add(item)
+	}
+	feed.Updated = last_modified
+	if !last_modified.IsZero() {
+		feed.Created = data.Posts[len(data.Posts)-1].PublishedAt
+	}
+
+	etag := `W/"` + hex.EncodeToString(hash.Sum(nil)) + `"`
+	c.Set(fiber.HeaderETag, etag)
+	if !last_modified.IsZero() {
+		c.Set(fiber.HeaderLastModified, last_modified.UTC().Format(http.TimeFormat))
+	}
+
+	if feedNotModified(c, etag, last_modified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	var body string
+	switch format {
+	case FeedAtom:
+		c.Set(fiber.HeaderContentType, "application/atom+xml; charset=utf-8")
+		body, err = feed.ToAtom()
+	case FeedJSON:
+		c.Set(fiber.HeaderContentType, "application/feed+json; charset=utf-8")
+		body, err = feed.ToJSON()
+	default:
+		c.Set(fiber.HeaderContentType, "application/rss+xml; charset=utf-8")
+		body, err = feed.ToRss()
+	}
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	return c.SendString(body)
+}
+
+// feedNotModified reports whether the client's cached copy is still fresh. If-None-Match wins over
+// If-Modified-Since, as in RFC 9110.
+func feedNotModified(c *fiber.Ctx, etag string, last_modified time.Time) bool {
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := c.Get(fiber.HeaderIfModifiedSince); since != "" && !last_modified.IsZero() {
+		if t, err := http.ParseTime(since); err == nil {
+			return !last_modified.Truncate(time.Second).After(t)
+		}
+	}
+
+	return false
+}
+
+// feedEnclosure turns a post thumbnail into an absolute enclosure. The size is read from the storage
+// for stored files and left as 0, meaning unknown, for remote images.
+func feedEnclosure(base string, post model.FeedPost) *feeds.Enclosure {
+	if post.Thumbnail == "" {
+		return nil
+	}
+
+	url := post.Thumbnail
+	if strings.HasPrefix(url, "/") {
+		url = base + url
+	}
+
+	mime_type := mime.TypeByExtension(strings.ToLower(filepath.Ext(strings.SplitN(url, "?", 2)[0])))
+	if mime_type == "" {
+		mime_type = "image/jpeg"
+	}
+
+	length := "0"
+	storage := fileHelper.GetStorage()
+	key, ok := fileHelper.KeyOf(storage, post.Thumbnail)
+	if !ok {
+		key, ok = fileHelper.KeyOf(storage, url)
+	}
+	if ok {
+		if size, err := storage.Size(key); err == nil {
+			length = strconv.FormatInt(size, 10)
+		}
+	}
+
+	return &feeds.Enclosure{Url: url, Type: mime_type, Length: length}
+}
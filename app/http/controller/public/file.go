@@ -0,0 +1,55 @@
+package public
+
+import (
+	"mime"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/file"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PrivateFile func gets a private file.
+// @Description Get a private file through a signed URL. Links to private files come with their expires and signature.
+// @Summary Get private file
+// @Tags File
+// @Produce octet-stream
+// @Param path path string true "Path of the file under private/"
+// @Param expires query integer true "Unix time the link stops working"
+// @Param signature query string true "Signature of the path and expiry"
+// @Success 200 {file} file "File"
+// @Failure 403,404 {object} response.ErrorResponse "Error"
+// @Router /upload/private/{path} [get]
+func PrivateFile(c *fiber.Ctx) error {
+	key, err := file.Key(file.PrivatePrefix, c.Params("*"))
+	if err != nil || !file.IsPrivate(key) {
+		return response.NotFound(c, file.ErrNotFound)
+	}
+
+	expires := c.Query("expires")
+	if err := file.Verify(key, expires, c.Query("signature")); err != nil {
+		return response.Forbidden(c, err)
+	}
+
+	reader, err := file.GetStorage().Get(key)
+	if err != nil {
+		if err == file.ErrNotFound {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	// The link works until it expires, so caches may keep the file that long and no longer.
+	unix, _ := strconv.ParseInt(expires, 10, 64)
+	c.Set(fiber.HeaderCacheControl, "private, max-age="+strconv.FormatInt(max(0, unix-time.Now().Unix()), 10))
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		c.Set(fiber.HeaderContentType, contentType)
+	} else {
+		c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+	}
+
+	return c.SendStream(reader)
+}
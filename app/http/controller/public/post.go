@@ -2,12 +2,14 @@ package public
 
 import (
 	"database/sql"
-	"log"
+	"strconv"
 
 	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/public"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/paginate"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/related"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/viewcount"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -17,6 +19,12 @@ import (
 // @Tags Public Post
 // @Accept json
 // @Produce json
+// @Param page query integer false "Page"
+// @Param limit query integer false "Limit"
+// @Param search query string false "Search"
+// @Param search_mode query string false "Search Mode" Enums(natural, boolean)
+// @Param sort_by query string false "Sort By" Enums(posts.id, title, relevance)
+// @Param sort query string false "Sort" Enums(ASC, DESC)
 // @Success 200 {object} response.PublicPostsResponse
 // @Failure 400,403,404 {object} response.ErrorResponse "Error"
 // @Router /api/v1/public/post [get]
@@ -29,7 +37,7 @@ func PostIndex(c *fiber.Ctx) error {
 
 	repository := repo.NewPostRepo(database.GetDB())
 
-	posts, count, err := repository.Index(limit, uint(limit*(page-1)), search, sort_by, sort)
+	posts, count, err := repository.Index(limit, uint(limit*(page-1)), search, c.Query("search_mode"), sort_by, sort)
 
 	if err != nil {
 		return response.InternalServerError(c, err)
@@ -46,6 +54,7 @@ func PostIndex(c *fiber.Ctx) error {
 // @Produce json
 // @Param slug path string true "Tag Slug" default(tag-1)
 // @Success 200 {object} response.PublicPostsByTagResponse
+// @Success 301 {object} response.RedirectResponse "Tag slug changed"
 // @Failure 400,403,404 {object} response.ErrorResponse "Error"
 // @Router /api/v1/public/post/tag/{slug} [get]
 func TagPost(c *fiber.Ctx) error {
@@ -53,13 +62,19 @@ func TagPost(c *fiber.Ctx) error {
 	slug := c.Params("slug")
 	repository := repo.NewPostRepo(database.GetDB())
 
+	if sort_by == "id" {
+		sort_by = "posts.id"
+	}
+
 	posts, count, err := repository.TagPost(slug, limit, uint(limit*(page-1)), search, sort_by, sort)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
+			if current, err := repo.NewSlugRedirectRepo(database.GetDB()).Tag(slug); err == nil {
+				return response.MovedPermanently(c, redirectLocation(c, "/api/v1/public/post/tag/"+current))
+			}
 			return response.NotFound(c, err)
 		} else {
-			log.Println(err)
 			return response.InternalServerError(c, err)
 		}
 	}
@@ -82,6 +97,10 @@ func UserPost(c *fiber.Ctx) error {
 	username := c.Params("username")
 	repository := repo.NewPostRepo(database.GetDB())
 
+	if sort_by == "id" {
+		sort_by = "posts.id"
+	}
+
 	posts, count, err := repository.UserPost(username, limit, uint(limit*(page-1)), search, sort_by, sort)
 
 	if err != nil {
@@ -103,6 +122,7 @@ func UserPost(c *fiber.Ctx) error {
 // @Produce json
 // @Param slug path string true "Post Slug" default(title-1)
 // @Success 200 {object} response.PostResponse
+// @Success 301 {object} response.RedirectResponse "Post slug changed"
 // @Failure 400,403,404 {object} response.ErrorResponse "Error"
 // @Router /api/v1/public/post/{slug} [get]
 func PostShow(c *fiber.Ctx) error {
@@ -113,11 +133,112 @@ func PostShow(c *fiber.Ctx) error {
 
 	if err != nil {
 		if err == sql.ErrNoRows {
+			if current, err := repo.NewSlugRedirectRepo(database.GetDB()).Post(slug); err == nil {
+				return response.MovedPermanently(c, redirectLocation(c, "/api/v1/public/post/"+current))
+			}
 			return response.NotFound(c, err)
 		} else {
 			return response.InternalServerError(c, err)
 		}
 	}
 
+	viewcount.GetCounter().Record(post.UUID.String(), c.IP())
+
 	return response.Show(c, post)
 }
+
+// relatedLimit caps how many related posts can be asked for, which also bounds the cache per post.
+const relatedLimit = 20
+
+// PublicPostRelated func gets related post.
+// @Description Get the published posts sharing the most tags and keywords with a post, newer first on ties.
+// @Summary Get related post
+// @Tags Public Post
+// @Accept json
+// @Produce json
+// @Param slug path string true "Post Slug" default(title-1)
+// @Param limit query integer false "Limit" default(5)
+// @Success 200 {object} response.PublicRelatedPostsResponse
+// @Failure 400,403,404 {object} response.ErrorResponse "Error"
+// @Router /api/v1/public/post/{slug}/related [get]
+func PostRelated(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+	limit := c.QueryInt("limit", 5)
+	if limit < 1 || limit > relatedLimit {
+		limit = relatedLimit
+	}
+
+	repository := repo.NewPostRepo(database.GetDB())
+	posts, err := related.Cached(slug+"|"+strconv.Itoa(limit), func() (interface{}, error) {
+		return repository.Related(slug, limit)
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return response.NotFound(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Show(c, posts)
+}
+
+// PublicPostPopular func gets popular post.
+// @Description Get the most viewed posts of the last day, week or month.
+// @Summary Get popular post
+// @Tags Public Post
+// @Accept json
+// @Produce json
+// @Param period query string false "Period" Enums(day, week, month) default(week)
+// @Param limit query integer false "Limit"
+// @Success 200 {object} response.PublicRankedPostsResponse
+// @Failure 400,403,404 {object} response.ErrorResponse "Error"
+// @Router /api/v1/public/post/popular [get]
+func PostPopular(c *fiber.Ctx) error {
+	_, limit, _, _, _ := paginate.Paginate(c)
+
+	repository := repo.NewPostViewRepo(database.GetDB())
+	posts, err := repository.Popular(c.Query("period", "week"), limit)
+
+	if err != nil {
+		if err == repo.ErrInvalidPeriod {
+			return response.BadRequest(c, err)
+		} else {
+			return response.InternalServerError(c, err)
+		}
+	}
+
+	return response.Show(c, posts)
+}
+
+// PublicPostTrending func gets trending post.
+// @Description Get the posts gaining views fastest over the last week, recent views weighing more.
+// @Summary Get trending post
+// @Tags Public Post
+// @Accept json
+// @Produce json
+// @Param limit query integer false "Limit"
+// @Success 200 {object} response.PublicRankedPostsResponse
+// @Failure 400,403,404 {object} response.ErrorResponse "Error"
+// @Router /api/v1/public/post/trending [get]
+func PostTrending(c *fiber.Ctx) error {
+	_, limit, _, _, _ := paginate.Paginate(c)
+
+	repository := repo.NewPostViewRepo(database.GetDB())
+	posts, err := repository.Trending(limit)
+
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	return response.Show(c, posts)
+}
+
+// redirectLocation keeps the query string of the request on a redirect to path.
+func redirectLocation(c *fiber.Ctx, path string) string {
+	if query := string(c.Request().URI().QueryString()); query != "" {
+		return path + "?" + query
+	}
+	return path
+}
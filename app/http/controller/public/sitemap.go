@@ -0,0 +1,67 @@
+package public
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/public"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/sitemap"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Sitemap func gets the sitemap.
+// @Description Get the sitemap of published posts, tags and authors. Past 50,000 URLs sitemap.xml becomes an index of sitemap-{page}.xml files.
+// @Summary Get sitemap
+// @Tags Sitemap
+// @Produce xml
+// @Success 200 {string} string "Sitemap"
+// @Failure 404 {object} response.ErrorResponse "Error"
+// @Router /sitemap.xml [get]
+// @Router /sitemap-{page}.xml [get]
+func Sitemap(c *fiber.Ctx) error {
+	name := "sitemap.xml"
+	if page := c.Params("page"); page != "" {
+		name = "sitemap-" + page + ".xml"
+	}
+
+	files, err := sitemap.Cached(SitemapFiles)
+	if err != nil {
+		return response.InternalServerError(c, err)
+	}
+
+	body, ok := files[name]
+	if !ok {
+		return response.NotFound(c, errors.New("sitemap not found"))
+	}
+
+	c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+	return c.Send(body)
+}
+
+// SitemapFiles builds the sitemap files from the public post repository, with absolute links built from APP_FULL_URL.
+func SitemapFiles() (map[string][]byte, error) {
+	repository := repo.NewPostRepo(database.GetDB())
+	entries, err := repository.Sitemap()
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimRight(os.Getenv("APP_FULL_URL"), "/")
+
+	urls := make([]sitemap.URL, 0, len(entries))
+	for _, entry := range entries {
+		path := "/api/v1/public/post/" + entry.Slug
+		switch entry.Type {
+		case "tag":
+			path = "/api/v1/public/post/tag/" + entry.Slug
+		case "user":
+			path = "/api/v1/public/post/user/" + entry.Slug
+		}
+		urls = append(urls, sitemap.URL{Loc: base + path, LastMod: entry.LastMod})
+	}
+
+	return sitemap.Generate(base, urls)
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/logger"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// AccessLog gives every request a logger with its ID and method, and writes one line for it once
+// answered, with the status, latency, size and client IP, and the error of failed ones.
+func AccessLog() func(*fiber.Ctx) error {
+	middleware := func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		logger.SetRequest(c, logrus.NewEntry(logger.GetLogger().Logger).WithFields(logrus.Fields{
+			"request_id": c.GetRespHeader(fiber.HeaderXRequestID),
+			"method":     c.Method(),
+		}))
+
+		// Answer errors here, so the line has the status they are sent with.
+		if err := c.Next(); err != nil {
+			if err := c.App().Config().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		entry := logger.Request(c).WithFields(logrus.Fields{
+			"path":       c.Path(),
+			"status":     status,
+			"latency_ms": time.Since(start).Milliseconds(),
+			"bytes":      len(c.Response().Body()),
+			"ip":         c.IP(),
+		})
+
+		failed, _ := c.Locals(response.LocalsKey).(*response.Error)
+		if failed != nil {
+			entry = entry.WithField("code", failed.Code)
+			if failed.Err != nil {
+				entry = entry.WithError(failed.Err)
+			}
+		}
+
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			entry.Error("request failed")
+		case status >= fiber.StatusBadRequest:
+			entry.Warn("request rejected")
+		default:
+			entry.Info("request")
+		}
+
+		return nil
+	}
+
+	return middleware
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
 	"github.com/gofiber/fiber/v2"
 	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
 )
@@ -14,10 +15,7 @@ func Email() func(*fiber.Ctx) error {
 		if email_verified_at != nil {
 			return c.Next()
 		} else {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  false,
-				"message": "Please verify your email first!",
-			})
+			return response.NewError(fiber.StatusUnauthorized, response.CodeEmailNotVerified, "Please verify your email first!")
 		}
 	}
 	return middleware
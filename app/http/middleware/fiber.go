@@ -3,17 +3,20 @@ package middleware
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
 func FiberMiddleware(a *fiber.App) {
 	a.Use(
+		// Take the X-Request-ID of the request or make one, and send it back.
+		RequestID(),
+		// Log every request as JSON, with its ID.
+		AccessLog(),
 		// Add CORS to each route.
 		cors.New(cors.Config{
 			AllowOrigins: "https://gofiber.io, https://gofiber.net",
-			AllowHeaders: "Origin, Content-Type, Accept",
+			AllowHeaders: "Origin, Content-Type, Accept, Tus-Resumable, Upload-Length, Upload-Offset, Upload-Metadata, Upload-Checksum, X-Request-ID",
+			// Let browser clients read the request ID and the headers of resumable uploads.
+			ExposeHeaders: "Location, X-Request-ID, Tus-Resumable, Tus-Version, Tus-Extension, Tus-Max-Size, Tus-Checksum-Algorithm, Upload-Offset, Upload-Length, Upload-Expires",
 		}),
-		// Add simple logger.
-		logger.New(),
 	)
 }
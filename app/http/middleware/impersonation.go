@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	authRepo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/auth"
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/logger"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
+)
+
+// AuditImpersonation rejects impersonation tokens whose session was stopped and records every write
+// request made with the others, keeping both the impersonated user and the impersonator. It goes
+// after JWTProtected or JWTOptional.
+func AuditImpersonation() func(*fiber.Ctx) error {
+	middleware := func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(*JWTTokenAuthed.Token)
+		if !ok {
+			// A guest on a route where the token is optional.
+			return c.Next()
+		}
+		claims := user.Claims.(JWTTokenAuthed.MapClaims)
+		impersonator_id, ok := claims["impersonator_id"].(string)
+
+		if !ok || impersonator_id == "" {
+			return c.Next()
+		}
+
+		impersonation_id, _ := claims["jti"].(string)
+		active, err := authRepo.NewAuthRepo(database.GetDB()).ImpersonationActive(impersonation_id)
+		if err != nil {
+			return response.InternalServerError(c, err)
+		}
+		if !active {
+			return response.NewError(fiber.StatusUnauthorized, response.CodeTokenInvalid, "The impersonation has ended")
+		}
+
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+
+		err = c.Next()
+
+		user_id, _ := claims["user_id"].(string)
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = response.From(err).Status
+		}
+
+		repository := repo.NewAuditLogRepo(database.GetDB())
+		if auditErr := repository.Store(&model.StoreAuditLog{
+			UserUUID:         user_id,
+			ImpersonatorUUID: &impersonator_id,
+			Method:           c.Method(),
+			Path:             c.OriginalURL(),
+			Status:           status,
+		}); auditErr != nil {
+			logger.Request(c).WithError(auditErr).Error("failed writing audit log")
+		}
+
+		return err
+	}
+
+	return middleware
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
 	"github.com/gofiber/fiber/v2"
 	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
 )
@@ -14,10 +15,7 @@ func IsActive() func(*fiber.Ctx) error {
 		if is_active == true {
 			return c.Next()
 		} else {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  false,
-				"message": "You are not an active user!",
-			})
+			return response.NewError(fiber.StatusUnauthorized, response.CodeUserInactive, "You are not an active user!")
 		}
 	}
 	return middleware
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/logger"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
 	"github.com/gofiber/fiber/v2"
 	jwtware "github.com/gofiber/jwt/v2"
 	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
@@ -21,28 +23,39 @@ func JWTProtected() func(*fiber.Ctx) error {
 	return jwtware.New(jwtwareConfig)
 }
 
+// JWTOptional authenticates the request when an Authorization header is sent and lets guests through otherwise.
+func JWTOptional() func(*fiber.Ctx) error {
+	protected := JWTProtected()
+
+	return func(c *fiber.Ctx) error {
+		if c.Get(fiber.HeaderAuthorization) == "" {
+			return c.Next()
+		}
+		return protected(c)
+	}
+}
+
 func verifyTokenExpiration(c *fiber.Ctx) error {
 	user := c.Locals("user").(*JWTTokenAuthed.Token)
 	claims := user.Claims.(JWTTokenAuthed.MapClaims)
 	expires := int64(claims["exp"].(float64))
 	if time.Now().Unix() > expires {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  false,
-			"message": errors.New("token expired"),
-		})
+		return response.NewError(fiber.StatusUnauthorized, response.CodeTokenExpired, "Token expired")
+	}
+
+	logger.AddField(c, "user_id", claims["user_id"])
+	if impersonator_id, ok := claims["impersonator_id"]; ok {
+		logger.AddField(c, "impersonator_id", impersonator_id)
 	}
 	return c.Next()
 }
 
 func jwtError(c *fiber.Ctx, err error) error {
 	if err.Error() == "Missing or malformed JWT" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  false,
-			"message": err.Error(),
-		})
+		return response.NewError(fiber.StatusBadRequest, response.CodeTokenMissing, err.Error())
+	}
+	if errors.Is(err, JWTTokenAuthed.ErrTokenExpired) {
+		return response.NewError(fiber.StatusUnauthorized, response.CodeTokenExpired, "Token expired")
 	}
-	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-		"status":  false,
-		"message": err.Error(),
-	})
+	return response.NewError(fiber.StatusUnauthorized, response.CodeTokenInvalid, err.Error()).WithCause(err)
 }
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
+)
+
+// CommentLimiter limits how many comments a user, or a guest by IP, may post per minute. It goes
+// after JWTOptional.
+func CommentLimiter() func(*fiber.Ctx) error {
+	return limiter.New(limiter.Config{
+		Max:        config.AppCfg().CommentRateLimit,
+		Expiration: time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			if user, ok := c.Locals("user").(*JWTTokenAuthed.Token); ok {
+				claims := user.Claims.(JWTTokenAuthed.MapClaims)
+				if user_id, ok := claims["user_id"].(string); ok {
+					return "user:" + user_id
+				}
+			}
+			return "ip:" + c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return response.NewError(fiber.StatusTooManyRequests, response.CodeTooManyRequests, "Too many comments, please try again later")
+		},
+	})
+}
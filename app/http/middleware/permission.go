@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
 	"github.com/gofiber/fiber/v2"
 	JWTTokenAuthed "github.com/golang-jwt/jwt/v4"
 )
@@ -13,10 +14,7 @@ func Permission(Permission string) func(*fiber.Ctx) error {
 
 		permissionsSlice, ok := permissions.([]interface{})
 		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  false,
-				"message": "You dont have permission!",
-			})
+			return response.NewError(fiber.StatusForbidden, response.CodePermissionDenied, "You dont have permission!")
 		}
 
 		var permissionsArray []string
@@ -37,10 +35,7 @@ func Permission(Permission string) func(*fiber.Ctx) error {
 		if found {
 			return c.Next()
 		} else {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  false,
-				"message": "You dont have permission!",
-			})
+			return response.NewError(fiber.StatusForbidden, response.CodePermissionDenied, "You dont have permission!")
 		}
 	}
 
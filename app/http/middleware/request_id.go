@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+// requestIDFormat is what a client sent X-Request-ID must look like to be kept, enough for UUIDs and
+// the IDs of usual proxies without letting clients put anything else in the logs.
+var requestIDFormat = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID takes the X-Request-ID of the request when it looks like an ID or makes one, and sends it
+// back.
+func RequestID() func(*fiber.Ctx) error {
+	generate := requestid.New()
+
+	return func(c *fiber.Ctx) error {
+		if id := c.Get(fiber.HeaderXRequestID); id != "" && !requestIDFormat.MatchString(id) {
+			c.Request().Header.Del(fiber.HeaderXRequestID)
+		}
+		return generate(c)
+	}
+}
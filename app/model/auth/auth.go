@@ -21,17 +21,17 @@ type User struct {
 }
 
 type Login struct {
-	Username string `json:"username" form:"username"`
+	Username string `json:"username" form:"username" validate:"required"`
 	Email    string `json:"email" form:"email"`
-	Password string `json:"password" form:"password"`
+	Password string `json:"password" form:"password" validate:"required"`
 }
 
 type Register struct {
-	Name     string `json:"name" form:"name"`
-	Username string `json:"username" form:"username"`
-	Email    string `json:"email" form:"email"`
+	Name     string `json:"name" form:"name" validate:"required,max=255"`
+	Username string `json:"username" form:"username" validate:"required,max=255,unique=users.username"`
+	Email    string `json:"email" form:"email" validate:"required,email,max=255,unique=users.email"`
 	RoleUUID string `json:"role_uuid" form:"role_uuid"`
-	Password string `json:"password" form:"password"`
+	Password string `json:"password" form:"password" validate:"required,min=8,max=72"`
 }
 
 type Role struct {
@@ -50,10 +50,15 @@ type RoleHasPermission struct {
 }
 
 type ForgotPassword struct {
-	Username string `json:"username" form:"username"`
+	Username string `json:"username" form:"username" validate:"required"`
 }
 
 type ChangeForgotPassword struct {
 	Token    string `json:"token" form:"token"`
-	Password string `json:"password" form:"password"`
+	Password string `json:"password" form:"password" validate:"required,min=8,max=72"`
+}
+
+type Can struct {
+	Permission   []string `json:"permission" form:"permission" validate:"required,min=1,dive,required"`
+	ResourceUUID []string `json:"resource_uuid" form:"resource_uuid" validate:"omitempty,dive,omitempty,uuid"`
 }
@@ -0,0 +1,25 @@
+package dashboard
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditLog struct {
+	UUID             uuid.UUID `db:"uuid" json:"uuid"`
+	UserUUID         string    `db:"user_uuid" json:"user_uuid"`
+	ImpersonatorUUID *string   `db:"impersonator_uuid" json:"impersonator_uuid"`
+	Method           string    `db:"method" json:"method"`
+	Path             string    `db:"path" json:"path"`
+	Status           int       `db:"status" json:"status"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+}
+
+type StoreAuditLog struct {
+	UserUUID         string
+	ImpersonatorUUID *string
+	Method           string
+	Path             string
+	Status           int
+}
@@ -0,0 +1,47 @@
+package dashboard
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CategoryMaxDepth is how many levels deep categories can nest, a root category being level 1.
+const CategoryMaxDepth = 3
+
+type Category struct {
+	UUID       uuid.UUID  `db:"uuid" json:"uuid"`
+	ParentUUID *string    `db:"parent_uuid" json:"parent_uuid"`
+	Name       string     `db:"name" json:"name"`
+	Slug       string     `db:"slug" json:"slug"`
+	IsActive   bool       `db:"is_active" json:"is_active"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt  *time.Time `db:"updated_at" json:"updated_at"`
+	DeletedAt  *time.Time `db:"deleted_at" json:"deleted_at"`
+}
+
+type CategoryShow struct {
+	UUID       uuid.UUID  `db:"uuid" json:"uuid"`
+	ParentUUID *string    `db:"parent_uuid" json:"parent_uuid"`
+	Name       string     `db:"name" json:"name"`
+	Slug       string     `db:"slug" json:"slug"`
+	IsActive   bool       `db:"is_active" json:"is_active"`
+	Depth      int        `db:"depth" json:"depth"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt  *time.Time `db:"updated_at" json:"updated_at"`
+	DeletedAt  *time.Time `db:"deleted_at" json:"deleted_at"`
+}
+
+type StoreCategory struct {
+	ParentUUID string `json:"parent_uuid" form:"parent_uuid" validate:"omitempty,uuid,exists=categories.uuid"`
+	Name       string `json:"name" form:"name" validate:"required,max=255"`
+	Slug       string `json:"slug" form:"slug"`
+	IsActive   bool   `json:"is_active" form:"is_active"`
+}
+
+type UpdateCategory struct {
+	ParentUUID string `json:"parent_uuid" form:"parent_uuid" validate:"omitempty,uuid,exists=categories.uuid"`
+	Name       string `json:"name" form:"name" validate:"required,max=255"`
+	Slug       string `json:"slug" form:"slug"`
+	IsActive   bool   `json:"is_active" form:"is_active"`
+}
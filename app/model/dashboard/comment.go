@@ -0,0 +1,40 @@
+package dashboard
+
+import (
+	"time"
+
+	jsonutil "github.com/arif-x/sqlx-mysql-boilerplate/pkg/json"
+	"github.com/google/uuid"
+)
+
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+)
+
+type Comment struct {
+	UUID       uuid.UUID         `db:"uuid" json:"uuid"`
+	PostUUID   uuid.UUID         `db:"post_uuid" json:"post_uuid"`
+	RootUUID   *uuid.UUID        `db:"root_uuid" json:"root_uuid"`
+	ParentUUID *uuid.UUID        `db:"parent_uuid" json:"parent_uuid"`
+	UserUUID   *uuid.UUID        `db:"user_uuid" json:"user_uuid"`
+	GuestName  *string           `db:"guest_name" json:"guest_name"`
+	GuestEmail *string           `db:"guest_email" json:"guest_email"`
+	Content    string            `db:"content" json:"content"`
+	Status     string            `db:"status" json:"status"`
+	CreatedAt  time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt  *time.Time        `db:"updated_at" json:"updated_at"`
+	DeletedAt  *time.Time        `db:"deleted_at" json:"deleted_at"`
+	User       *jsonutil.JSONRaw `db:"user" json:"user"`
+	Post       *jsonutil.JSONRaw `db:"post" json:"post"`
+}
+
+type UpdateComment struct {
+	Content string `json:"content" form:"content" validate:"required,max=5000"`
+}
+
+type BulkComment struct {
+	UUID   []string `json:"uuid" form:"uuid" validate:"required,min=1,dive,uuid"`
+	Action string   `json:"action" form:"action" validate:"required,oneof=approve reject delete"`
+}
@@ -0,0 +1,56 @@
+package dashboard
+
+import (
+	"time"
+
+	jsonutil "github.com/arif-x/sqlx-mysql-boilerplate/pkg/json"
+	"github.com/google/uuid"
+)
+
+const (
+	// MediaOriginLibrary is media uploaded to the library, kept until deleted.
+	MediaOriginLibrary = "library"
+	// MediaOriginThumbnail is media uploaded along with a post, pruned once no post or revision uses it.
+	MediaOriginThumbnail = "thumbnail"
+)
+
+type Media struct {
+	UUID        uuid.UUID         `db:"uuid" json:"uuid"`
+	UserUUID    *string           `db:"user_uuid" json:"user_uuid"`
+	StorageKey  string            `db:"storage_key" json:"storage_key"`
+	URL         string            `db:"url" json:"url"`
+	Filename    string            `db:"filename" json:"filename"`
+	ContentType string            `db:"content_type" json:"content_type"`
+	Size        int64             `db:"size" json:"size"`
+	Width       int               `db:"width" json:"width"`
+	Height      int               `db:"height" json:"height"`
+	Variants    *jsonutil.JSONRaw `db:"variants" json:"variants"`
+	AltText     string            `db:"alt_text" json:"alt_text"`
+	Caption     *string           `db:"caption" json:"caption"`
+	Origin      string            `db:"origin" json:"origin"`
+	IsPrivate   bool              `db:"is_private" json:"is_private"`
+	UsageCount  int               `db:"usage_count" json:"usage_count"`
+	CreatedAt   time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt   *time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+type StoreMedia struct {
+	AltText     string `json:"alt_text" form:"alt_text" validate:"max=255"`
+	Caption     string `json:"caption" form:"caption"`
+	IsPrivate   bool   `json:"is_private" form:"is_private"`
+	UserUUID    string `json:"-" form:"-"`
+	StorageKey  string `json:"-" form:"-"`
+	URL         string `json:"-" form:"-"`
+	Filename    string `json:"-" form:"-"`
+	ContentType string `json:"-" form:"-"`
+	Size        int64  `json:"-" form:"-"`
+	Width       int    `json:"-" form:"-"`
+	Height      int    `json:"-" form:"-"`
+	Variants    string `json:"-" form:"-"`
+	Origin      string `json:"-" form:"-"`
+}
+
+type UpdateMedia struct {
+	AltText string `json:"alt_text" form:"alt_text" validate:"max=255"`
+	Caption string `json:"caption" form:"caption"`
+}
@@ -23,9 +23,9 @@ type ShowPermission struct {
 }
 
 type StorePermission struct {
-	Name string `json:"name" form:"name"`
+	Name string `json:"name" form:"name" validate:"required,max=255,unique=permissions.name"`
 }
 
 type UpdatePermission struct {
-	Name string `json:"name" form:"name"`
+	Name string `json:"name" form:"name" validate:"required,max=255,unique=permissions.name"`
 }
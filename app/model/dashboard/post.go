@@ -7,61 +7,101 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	PostStatusDraft     = "draft"
+	PostStatusInReview  = "in_review"
+	PostStatusScheduled = "scheduled"
+	PostStatusPublished = "published"
+	PostStatusArchived  = "archived"
+)
+
 type Post struct {
-	UUID        uuid.UUID         `db:"uuid" json:"uuid"`
-	TagUUID     uuid.UUID         `db:"tag_uuid" json:"tag_uuid"`
-	UserUUID    uuid.UUID         `db:"user_uuid" json:"user_uuid"`
-	Title       string            `db:"title" json:"title"`
-	Thumbnail   string            `db:"thumbnail" json:"thumbnail"`
-	Content     string            `db:"content" json:"content"`
-	Slug        string            `db:"slug" json:"slug"`
-	Keyword     string            `db:"keyword" json:"keyword"`
-	IsActive    bool              `db:"is_active" json:"is_active"`
-	IsHighlight bool              `db:"is_highlight" json:"is_highlight"`
-	CreatedAt   time.Time         `db:"created_at" json:"created_at"`
-	UpdatedAt   *time.Time        `db:"updated_at" json:"updated_at"`
-	DeletedAt   *time.Time        `db:"deleted_at" json:"deleted_at"`
-	User        *jsonutil.JSONRaw `db:"user" json:"user"`
-	Tag         *jsonutil.JSONRaw `db:"tag" json:"tag"`
+	UUID               uuid.UUID         `db:"uuid" json:"uuid"`
+	UserUUID           uuid.UUID         `db:"user_uuid" json:"user_uuid"`
+	CategoryUUID       *string           `db:"category_uuid" json:"category_uuid"`
+	Title              string            `db:"title" json:"title"`
+	Thumbnail          string            `db:"thumbnail" json:"thumbnail"`
+	ThumbnailVariants  *jsonutil.JSONRaw `db:"thumbnail_variants" json:"thumbnail_variants"`
+	ThumbnailMediaUUID *string           `db:"thumbnail_media_uuid" json:"thumbnail_media_uuid"`
+	Content            string            `db:"content" json:"content"`
+	ContentFormat      string            `db:"content_format" json:"content_format"`
+	Slug               string            `db:"slug" json:"slug"`
+	SlugLocked         bool              `db:"slug_locked" json:"slug_locked"`
+	Keyword            string            `db:"keyword" json:"keyword"`
+	Status             string            `db:"status" json:"status"`
+	PublishedAt        *time.Time        `db:"published_at" json:"published_at"`
+	IsActive           bool              `db:"is_active" json:"is_active"`
+	IsHighlight        bool              `db:"is_highlight" json:"is_highlight"`
+	ViewCount          int64             `db:"view_count" json:"view_count"`
+	CreatedAt          time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt          *time.Time        `db:"updated_at" json:"updated_at"`
+	DeletedAt          *time.Time        `db:"deleted_at" json:"deleted_at"`
+	User               *jsonutil.JSONRaw `db:"user" json:"user"`
+	Tags               *jsonutil.JSONRaw `db:"tags" json:"tags"`
+	Relevance          *float64          `db:"relevance" json:"relevance"`
+	Snippet            *string           `db:"-" json:"snippet"`
 }
 
 type PostShow struct {
-	UUID        uuid.UUID         `db:"uuid" json:"uuid"`
-	TagUUID     uuid.UUID         `db:"tag_uuid" json:"tag_uuid"`
-	UserUUID    uuid.UUID         `db:"user_uuid" json:"user_uuid"`
-	Thumbnail   string            `db:"thumbnail" json:"thumbnail"`
-	Title       string            `db:"title" json:"title"`
-	Content     string            `db:"content" json:"content"`
-	Slug        string            `db:"slug" json:"slug"`
-	Keyword     string            `db:"keyword" json:"keyword"`
-	IsActive    bool              `db:"is_active" json:"is_active"`
-	IsHighlight bool              `db:"is_highlight" json:"is_highlight"`
-	CreatedAt   time.Time         `db:"created_at" json:"created_at"`
-	UpdatedAt   *time.Time        `db:"updated_at" json:"updated_at"`
-	User        *jsonutil.JSONRaw `db:"user" json:"user"`
-	Tag         *jsonutil.JSONRaw `db:"tag" json:"tag"`
+	UUID               uuid.UUID         `db:"uuid" json:"uuid"`
+	UserUUID           uuid.UUID         `db:"user_uuid" json:"user_uuid"`
+	CategoryUUID       *string           `db:"category_uuid" json:"category_uuid"`
+	Thumbnail          string            `db:"thumbnail" json:"thumbnail"`
+	ThumbnailVariants  *jsonutil.JSONRaw `db:"thumbnail_variants" json:"thumbnail_variants"`
+	ThumbnailMediaUUID *string           `db:"thumbnail_media_uuid" json:"thumbnail_media_uuid"`
+	Title              string            `db:"title" json:"title"`
+	Content            string            `db:"content" json:"content"`
+	ContentFormat      string            `db:"content_format" json:"content_format"`
+	Slug               string            `db:"slug" json:"slug"`
+	SlugLocked         bool              `db:"slug_locked" json:"slug_locked"`
+	Keyword            string            `db:"keyword" json:"keyword"`
+	Status             string            `db:"status" json:"status"`
+	PublishedAt        *time.Time        `db:"published_at" json:"published_at"`
+	IsActive           bool              `db:"is_active" json:"is_active"`
+	IsHighlight        bool              `db:"is_highlight" json:"is_highlight"`
+	ViewCount          int64             `db:"view_count" json:"view_count"`
+	CreatedAt          time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt          *time.Time        `db:"updated_at" json:"updated_at"`
+	User               *jsonutil.JSONRaw `db:"user" json:"user"`
+	Tags               *jsonutil.JSONRaw `db:"tags" json:"tags"`
+}
+
+type PostThumbnail struct {
+	UUID              string  `db:"uuid" json:"uuid"`
+	Thumbnail         string  `db:"thumbnail" json:"thumbnail"`
+	ThumbnailVariants *string `db:"thumbnail_variants" json:"thumbnail_variants"`
 }
 
 type StorePost struct {
-	TagUUID     uuid.UUID `json:"tag_uuid" form:"tag_uuid"`
-	UserUUID    uuid.UUID `json:"user_uuid" form:"user_uuid"`
-	Title       string    `json:"title" form:"title"`
-	Thumbnail   string    `json:"thumbnail" form:"thumbnail"`
-	Content     string    `json:"content" form:"content"`
-	Slug        string    `json:"slug" form:"slug"`
-	Keyword     string    `json:"keyword" form:"keyword"`
-	IsActive    bool      `json:"is_active" form:"is_active"`
-	IsHighlight bool      `json:"is_highlight" form:"is_highlight"`
+	TagUUIDs           []string  `json:"tag_uuids" form:"tag_uuids" validate:"dive,uuid,exists=tags.uuid"`
+	UserUUID           uuid.UUID `json:"user_uuid" form:"user_uuid" validate:"required,exists=users.uuid"`
+	CategoryUUID       string    `json:"category_uuid" form:"category_uuid" validate:"omitempty,uuid,exists=categories.uuid"`
+	Title              string    `json:"title" form:"title" validate:"required,max=255"`
+	ThumbnailMediaUUID string    `json:"thumbnail_media_uuid" form:"thumbnail_media_uuid" validate:"omitempty,uuid,exists=media.uuid"`
+	Content            string    `json:"content" form:"content" validate:"required"`
+	ContentFormat      string    `json:"content_format" form:"content_format" validate:"omitempty,oneof=markdown html plain"`
+	Slug               string    `json:"slug" form:"slug"`
+	Keyword            string    `json:"keyword" form:"keyword"`
+	IsHighlight        bool      `json:"is_highlight" form:"is_highlight"`
+	EditorUUID         string    `json:"-" form:"-"`
 }
 
 type UpdatePost struct {
-	TagUUID     uuid.UUID `json:"tag_uuid" form:"tag_uuid"`
-	UserUUID    uuid.UUID `json:"user_uuid" form:"user_uuid"`
-	Title       string    `json:"title" form:"title"`
-	Thumbnail   string    `json:"thumbnail" form:"thumbnail"`
-	Content     string    `json:"content" form:"content"`
-	Slug        string    `json:"slug" form:"slug"`
-	Keyword     string    `json:"keyword" form:"keyword"`
-	IsActive    bool      `json:"is_active" form:"is_active"`
-	IsHighlight bool      `json:"is_highlight" form:"is_highlight"`
+	TagUUIDs           []string  `json:"tag_uuids" form:"tag_uuids" validate:"dive,uuid,exists=tags.uuid"`
+	UserUUID           uuid.UUID `json:"user_uuid" form:"user_uuid" validate:"required,exists=users.uuid"`
+	CategoryUUID       string    `json:"category_uuid" form:"category_uuid" validate:"omitempty,uuid,exists=categories.uuid"`
+	Title              string    `json:"title" form:"title" validate:"required,max=255"`
+	ThumbnailMediaUUID string    `json:"thumbnail_media_uuid" form:"thumbnail_media_uuid" validate:"omitempty,uuid,exists=media.uuid"`
+	Content            string    `json:"content" form:"content" validate:"required"`
+	ContentFormat      string    `json:"content_format" form:"content_format" validate:"omitempty,oneof=markdown html plain"`
+	Slug               string    `json:"slug" form:"slug"`
+	SlugLocked         *bool     `json:"slug_locked" form:"slug_locked"`
+	Keyword            string    `json:"keyword" form:"keyword"`
+	IsHighlight        bool      `json:"is_highlight" form:"is_highlight"`
+	EditorUUID         string    `json:"-" form:"-"`
+}
+
+type UpdatePostStatus struct {
+	Status      string `json:"status" form:"status" validate:"required,oneof=draft in_review scheduled published archived"`
+	PublishedAt string `json:"published_at" form:"published_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 }
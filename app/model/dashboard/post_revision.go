@@ -0,0 +1,28 @@
+package dashboard
+
+import (
+	"time"
+
+	jsonutil "github.com/arif-x/sqlx-mysql-boilerplate/pkg/json"
+	"github.com/google/uuid"
+)
+
+type PostRevision struct {
+	UUID          uuid.UUID         `db:"uuid" json:"uuid"`
+	PostUUID      uuid.UUID         `db:"post_uuid" json:"post_uuid"`
+	UserUUID      uuid.UUID         `db:"user_uuid" json:"user_uuid"`
+	Title         string            `db:"title" json:"title"`
+	Thumbnail     string            `db:"thumbnail" json:"thumbnail"`
+	Content       string            `db:"content" json:"content"`
+	ContentFormat string            `db:"content_format" json:"content_format"`
+	Keyword       string            `db:"keyword" json:"keyword"`
+	ChangedFields *jsonutil.JSONRaw `db:"changed_fields" json:"changed_fields"`
+	CreatedAt     time.Time         `db:"created_at" json:"created_at"`
+	User          *jsonutil.JSONRaw `db:"user" json:"user"`
+}
+
+type PostRevisionDiff struct {
+	From string            `json:"from"`
+	To   string            `json:"to"`
+	Diff map[string]string `json:"diff"`
+}
@@ -9,7 +9,9 @@ import (
 type Role struct {
 	UUID      uuid.UUID  `db:"uuid" json:"uuid"`
 	Name      string     `db:"name" json:"name"`
+	Code      *string    `db:"code" json:"code"`
 	IsActive  bool       `db:"is_active" json:"is_active"`
+	IsSystem  bool       `db:"is_system" json:"is_system"`
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt *time.Time `db:"updated_at" json:"updated_at"`
 	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at"`
@@ -18,18 +20,21 @@ type Role struct {
 type ShowRole struct {
 	UUID      uuid.UUID  `db:"uuid" json:"uuid"`
 	Name      string     `db:"name" json:"name"`
+	Code      *string    `db:"code" json:"code"`
 	IsActive  bool       `db:"is_active" json:"is_active"`
+	IsSystem  bool       `db:"is_system" json:"is_system"`
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt *time.Time `db:"updated_at" json:"updated_at"`
 	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at"`
 }
 
 type StoreRole struct {
-	Name     string `json:"name" form:"name"`
+	Name     string `json:"name" form:"name" validate:"required,max=255"`
+	Code     string `json:"code" form:"code" validate:"omitempty,max=100,unique=roles.code"`
 	IsActive bool   `json:"is_active" form:"is_active"`
 }
 
 type UpdateRole struct {
-	Name     string `json:"name" form:"name"`
+	Name     string `json:"name" form:"name" validate:"required,max=255"`
 	IsActive bool   `json:"is_active" form:"is_active"`
 }
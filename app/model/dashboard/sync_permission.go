@@ -12,5 +12,5 @@ type ShowSyncPermission struct {
 }
 
 type UpdateSyncPermission struct {
-	PermissionUUID []string `json:"permission_uuid" form:"permission_uuid"`
+	PermissionUUID []string `json:"permission_uuid" form:"permission_uuid" validate:"dive,uuid,exists=permissions.uuid"`
 }
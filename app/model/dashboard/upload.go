@@ -0,0 +1,30 @@
+package dashboard
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Upload struct {
+	UUID      uuid.UUID  `db:"uuid" json:"uuid"`
+	UserUUID  string     `db:"user_uuid" json:"user_uuid"`
+	Filename  string     `db:"filename" json:"filename"`
+	Length    int64      `db:"length" json:"length"`
+	Offset    int64      `db:"upload_offset" json:"offset"`
+	Checksum  *string    `db:"checksum" json:"checksum"`
+	IsPrivate bool       `db:"is_private" json:"is_private"`
+	MediaUUID *string    `db:"media_uuid" json:"media_uuid"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type StoreUpload struct {
+	UserUUID  string
+	Filename  string
+	Length    int64
+	Checksum  string
+	IsPrivate bool
+	ExpiresAt time.Time
+}
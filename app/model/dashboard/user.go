@@ -31,17 +31,17 @@ type UserShow struct {
 }
 
 type StoreUser struct {
-	Name     string `json:"name" form:"name"`
-	Username string `json:"username" form:"username"`
-	Email    string `json:"email" form:"email"`
-	Password string `json:"password" form:"password"`
-	RoleUUID string `json:"role_uuid" form:"role_uuid"`
+	Name     string `json:"name" form:"name" validate:"required,max=255"`
+	Username string `json:"username" form:"username" validate:"required,max=255,unique=users.username"`
+	Email    string `json:"email" form:"email" validate:"required,email,max=255,unique=users.email"`
+	Password string `json:"password" form:"password" validate:"required,min=8,max=72"`
+	RoleUUID string `json:"role_uuid" form:"role_uuid" validate:"required,uuid,exists=roles.uuid"`
 }
 
 type UpdateUser struct {
-	Name     string `json:"name" form:"name"`
-	Username string `json:"username" form:"username"`
-	Email    string `json:"email" form:"email"`
-	Password string `json:"password" form:"password"`
-	RoleUUID string `json:"role_uuid" form:"role_uuid"`
+	Name     string `json:"name" form:"name" validate:"required,max=255"`
+	Username string `json:"username" form:"username" validate:"required,max=255,unique=users.username"`
+	Email    string `json:"email" form:"email" validate:"required,email,max=255,unique=users.email"`
+	Password string `json:"password" form:"password" validate:"omitempty,min=8,max=72"`
+	RoleUUID string `json:"role_uuid" form:"role_uuid" validate:"required,uuid,exists=roles.uuid"`
 }
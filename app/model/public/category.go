@@ -0,0 +1,18 @@
+package public
+
+type CategoryNode struct {
+	UUID       string          `db:"uuid" json:"uuid"`
+	ParentUUID *string         `db:"parent_uuid" json:"parent_uuid"`
+	Name       string          `db:"name" json:"name"`
+	Slug       string          `db:"slug" json:"slug"`
+	PostCount  int             `db:"post_count" json:"post_count"`
+	Children   []*CategoryNode `db:"-" json:"children"`
+}
+
+type CategoryWithPost struct {
+	UUID               string `db:"uuid" json:"uuid"`
+	Name               string `db:"name" json:"name"`
+	Slug               string `db:"slug" json:"slug"`
+	IncludeDescendants bool   `db:"-" json:"include_descendants"`
+	Post               []Post `db:"-" json:"post"`
+}
@@ -0,0 +1,40 @@
+package public
+
+import (
+	"time"
+
+	jsonutil "github.com/arif-x/sqlx-mysql-boilerplate/pkg/json"
+	"github.com/google/uuid"
+)
+
+type Comment struct {
+	UUID       uuid.UUID         `db:"uuid" json:"uuid"`
+	PostUUID   uuid.UUID         `db:"post_uuid" json:"post_uuid"`
+	ParentUUID *uuid.UUID        `db:"parent_uuid" json:"parent_uuid"`
+	Name       string            `db:"name" json:"name"`
+	Content    string            `db:"content" json:"content"`
+	Status     string            `db:"status" json:"status"`
+	CreatedAt  time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt  *time.Time        `db:"updated_at" json:"updated_at"`
+	User       *jsonutil.JSONRaw `db:"user" json:"user"`
+	Replies    []Reply           `db:"-" json:"replies"`
+}
+
+type Reply struct {
+	UUID       uuid.UUID         `db:"uuid" json:"uuid"`
+	ParentUUID *uuid.UUID        `db:"parent_uuid" json:"parent_uuid"`
+	Name       string            `db:"name" json:"name"`
+	Content    string            `db:"content" json:"content"`
+	CreatedAt  time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt  *time.Time        `db:"updated_at" json:"updated_at"`
+	User       *jsonutil.JSONRaw `db:"user" json:"user"`
+}
+
+type StoreComment struct {
+	ParentUUID string `json:"parent_uuid" form:"parent_uuid" validate:"omitempty,uuid"`
+	Name       string `json:"name" form:"name" validate:"omitempty,max=255"`
+	Email      string `json:"email" form:"email" validate:"omitempty,email,max=255"`
+	Content    string `json:"content" form:"content" validate:"required,max=5000"`
+	UserUUID   string `json:"-" form:"-"`
+	Status     string `json:"-" form:"-"`
+}
@@ -0,0 +1,27 @@
+package public
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Feed struct {
+	Name  string
+	Posts []FeedPost
+}
+
+type FeedPost struct {
+	UUID        uuid.UUID  `db:"uuid"`
+	Title       string     `db:"title"`
+	Slug        string     `db:"slug"`
+	Thumbnail   string     `db:"thumbnail"`
+	Content     string     `db:"content"`
+	ContentHTML string     `db:"content_html"`
+	Excerpt     string     `db:"excerpt"`
+	Keyword     string     `db:"keyword"`
+	PublishedAt time.Time  `db:"published_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+	AuthorName  string     `db:"author_name"`
+	AuthorEmail string     `db:"author_email"`
+}
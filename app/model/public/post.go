@@ -8,57 +8,63 @@ import (
 )
 
 type Post struct {
-	UUID        uuid.UUID         `db:"uuid" json:"uuid"`
-	TagUUID     uuid.UUID         `db:"tag_uuid" json:"tag_uuid"`
-	UserUUID    uuid.UUID         `db:"user_uuid" json:"user_uuid"`
-	Title       string            `db:"title" json:"title"`
-	Thumbnail   string            `db:"thumbnail" json:"thumbnail"`
-	Content     string            `db:"content" json:"content"`
-	Keyword     string            `db:"keyword" json:"keyword"`
-	Slug        string            `db:"slug" json:"slug"`
-	IsActive    string            `db:"is_active" json:"is_active"`
-	IsHighlight string            `db:"is_highlight" json:"is_highlight"`
-	CreatedAt   time.Time         `db:"created_at" json:"created_at"`
-	UpdatedAt   *time.Time        `db:"updated_at" json:"updated_at"`
-	DeletedAt   *time.Time        `db:"deleted_at" json:"deleted_at"`
-	User        *jsonutil.JSONRaw `db:"user" json:"user"`
-	Tag         *jsonutil.JSONRaw `db:"tag" json:"tag"`
+	UUID               uuid.UUID         `db:"uuid" json:"uuid"`
+	UserUUID           uuid.UUID         `db:"user_uuid" json:"user_uuid"`
+	Title              string            `db:"title" json:"title"`
+	Thumbnail          string            `db:"thumbnail" json:"thumbnail"`
+	ThumbnailVariants  *jsonutil.JSONRaw `db:"thumbnail_variants" json:"thumbnail_variants"`
+	Content            string            `db:"content" json:"content"`
+	ContentFormat      string            `db:"content_format" json:"content_format"`
+	ContentHTML        *string           `db:"content_html" json:"content_html"`
+	Excerpt            *string           `db:"excerpt" json:"excerpt"`
+	ReadingTimeMinutes int               `db:"reading_time_minutes" json:"reading_time_minutes"`
+	TOC                *jsonutil.JSONRaw `db:"toc" json:"toc"`
+	Keyword            string            `db:"keyword" json:"keyword"`
+	Slug               string            `db:"slug" json:"slug"`
+	PublishedAt        *time.Time        `db:"published_at" json:"published_at"`
+	IsActive           string            `db:"is_active" json:"is_active"`
+	IsHighlight        string            `db:"is_highlight" json:"is_highlight"`
+	CommentCount       int               `db:"comment_count" json:"comment_count"`
+	CreatedAt          time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt          *time.Time        `db:"updated_at" json:"updated_at"`
+	DeletedAt          *time.Time        `db:"deleted_at" json:"deleted_at"`
+	User               *jsonutil.JSONRaw `db:"user" json:"user"`
+	Tags               *jsonutil.JSONRaw `db:"tags" json:"tags"`
+	Relevance          *float64          `db:"relevance" json:"relevance"`
+	Snippet            *string           `db:"-" json:"snippet"`
+}
+
+type RankedPost struct {
+	Post
+	ViewCount int64   `db:"view_count" json:"view_count"`
+	Score     float64 `db:"score" json:"score"`
+}
+
+type RelatedPost struct {
+	Post
+	Score float64 `db:"score" json:"score"`
 }
 
 type PostSingle struct {
-	UUID        uuid.UUID         `db:"uuid" json:"uuid"`
-	TagUUID     uuid.UUID         `db:"tag_uuid" json:"tag_uuid"`
-	UserUUID    uuid.UUID         `db:"user_uuid" json:"user_uuid"`
-	Title       string            `db:"title" json:"title"`
-	Thumbnail   string            `db:"thumbnail" json:"thumbnail"`
-	Content     string            `db:"content" json:"content"`
-	Keyword     string            `db:"keyword" json:"keyword"`
-	Slug        string            `db:"slug" json:"slug"`
-	IsActive    string            `db:"is_active" json:"is_active"`
-	IsHighlight string            `db:"is_highlight" json:"is_highlight"`
-	CreatedAt   time.Time         `db:"created_at" json:"created_at"`
-	UpdatedAt   *time.Time        `db:"updated_at" json:"updated_at"`
-	DeletedAt   *time.Time        `db:"deleted_at" json:"deleted_at"`
-	User        *jsonutil.JSONRaw `db:"user" json:"user"`
-	Tag         *jsonutil.JSONRaw `db:"tag" json:"tag"`
-	Similiar    []*Post           `db:"similiar" json:"similiar"`
+	Post
+	Similiar []*Post `db:"similiar" json:"similiar"`
 }
 
 type UserWithPost struct {
-	UUID      uuid.UUID         `db:"uuid" json:"uuid"`
-	Name      string            `db:"name" json:"name"`
-	Username  string            `db:"username" json:"username"`
-	Email     string            `db:"email" json:"email"`
-	CreatedAt time.Time         `db:"created_at" json:"created_at"`
-	UpdatedAt *time.Time        `db:"updated_at" json:"updated_at"`
-	Post      *jsonutil.JSONRaw `db:"post" json:"post"`
+	UUID      uuid.UUID  `db:"uuid" json:"uuid"`
+	Name      string     `db:"name" json:"name"`
+	Username  string     `db:"username" json:"username"`
+	Email     string     `db:"email" json:"email"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at" json:"updated_at"`
+	Post      []Post     `db:"-" json:"post"`
 }
 
 type TagWithPost struct {
-	UUID      uuid.UUID         `db:"uuid" json:"uuid"`
-	Name      string            `db:"name" json:"name"`
-	CreatedAt time.Time         `db:"created_at" json:"created_at"`
-	UpdatedAt *time.Time        `db:"updated_at" json:"updated_at"`
-	Post      *jsonutil.JSONRaw `db:"post" json:"post"`
-	Slug      string            `db:"slug" json:"slug"`
+	UUID      uuid.UUID  `db:"uuid" json:"uuid"`
+	Name      string     `db:"name" json:"name"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at" json:"updated_at"`
+	Post      []Post     `db:"-" json:"post"`
+	Slug      string     `db:"slug" json:"slug"`
 }
@@ -0,0 +1,9 @@
+package public
+
+import "time"
+
+type SitemapEntry struct {
+	Type    string     `db:"type"`
+	Slug    string     `db:"slug"`
+	LastMod *time.Time `db:"lastmod"`
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/auth"
@@ -15,6 +16,28 @@ type AuthRepository interface {
 	Verify(username string) (model.User, string, []string, error)
 	ForgotPassword(*model.ForgotPassword) (model.User, error)
 	ChangeForgotPassword(username string, password string) (model.User, string, []string, error)
+	Permissions(UserUUID string) ([]string, error)
+	ByUUID(UserUUID string) (model.User, string, []string, error)
+	IsOwner(resource string, ResourceUUID string, UserUUID string) (bool, error)
+	StartImpersonation(ID string, UserUUID string, ImpersonatorUUID string, ExpiresAt time.Time) error
+	StopImpersonation(ID string) (bool, error)
+	ImpersonationActive(ID string) (bool, error)
+}
+
+// ErrNoOwner is returned for ownership checks on resources whose rows have no owner.
+var ErrNoOwner = errors.New("The resource has no owner to check")
+
+// owners maps a permission resource to the check that a user owns one of its rows.
+var owners = map[string]func(db *database.DB, ResourceUUID string, UserUUID string) (bool, error){
+	"post": func(db *database.DB, ResourceUUID string, UserUUID string) (bool, error) {
+		return exists(db, `SELECT count(*) FROM posts WHERE uuid = ? AND user_uuid = ? AND deleted_at IS NULL`, ResourceUUID, UserUUID)
+	},
+	"user": func(db *database.DB, ResourceUUID string, UserUUID string) (bool, error) {
+		if ResourceUUID != UserUUID {
+			return false, nil
+		}
+		return exists(db, `SELECT count(*) FROM users WHERE uuid = ? AND deleted_at IS NULL`, ResourceUUID)
+	},
 }
 
 type AuthRepo struct {
@@ -86,7 +109,7 @@ func (repo *AuthRepo) Login(Username string) (model.User, string, []string, erro
 }
 
 func (repo *AuthRepo) Register(request *model.Register) (model.User, string, []string, error) {
-	inactive_q := "SELECT uuid FROM roles WHERE name = 'Inactive' LIMIT 1"
+	inactive_q := "SELECT uuid FROM roles WHERE code = 'inactive' LIMIT 1"
 	var inactive_role_uuid uuid.UUID
 	_ = repo.db.QueryRow(inactive_q).Scan(&inactive_role_uuid)
 
@@ -155,7 +178,7 @@ func (repo *AuthRepo) Register(request *model.Register) (model.User, string, []s
 
 func (repo *AuthRepo) Verify(username string) (model.User, string, []string, error) {
 	var new_role_uuid string
-	get_verified_role_uuid_query := `SELECT uuid FROM roles WHERE lower(name) = 'verified'`
+	get_verified_role_uuid_query := `SELECT uuid FROM roles WHERE code = 'verified' LIMIT 1`
 	verified_role_err := repo.db.QueryRowContext(context.Background(), get_verified_role_uuid_query).Scan(
 		&new_role_uuid,
 	)
@@ -311,6 +334,114 @@ func (repo *AuthRepo) ChangeForgotPassword(username string, password string) (mo
 	return user, role_name, permissions, err
 }
 
+func (repo *AuthRepo) Permissions(UserUUID string) ([]string, error) {
+	query := `SELECT permissions.name FROM users
+	JOIN role_has_permissions ON role_has_permissions.role_uuid = users.role_uuid
+	JOIN permissions ON permissions.uuid = role_has_permissions.permission_uuid
+	WHERE users.uuid = ? AND users.deleted_at IS NULL AND permissions.deleted_at IS NULL
+	ORDER BY permissions.name`
+
+	rows, err := repo.db.QueryContext(context.Background(), query, UserUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	permissions := []string{}
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (repo *AuthRepo) ByUUID(UserUUID string) (model.User, string, []string, error) {
+	var user model.User
+	query := `SELECT uuid, name, email, username, password, role_uuid, email_verified_at, is_active, created_at, updated_at, deleted_at FROM users
+	WHERE uuid = ? AND deleted_at IS NULL LIMIT 1`
+	err := repo.db.QueryRowContext(context.Background(), query, UserUUID).Scan(
+		&user.UUID,
+		&user.Name,
+		&user.Email,
+		&user.Username,
+		&user.Password,
+		&user.RoleUUID,
+		&user.EmailVerifiedAt,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.DeletedAt,
+	)
+	if err != nil {
+		return model.User{}, "", []string{}, err
+	}
+
+	var role_name string
+	get_role_name_query := `SELECT name FROM roles WHERE uuid = ?`
+	err = repo.db.QueryRowContext(context.Background(), get_role_name_query, user.RoleUUID).Scan(
+		&role_name,
+	)
+	if err != nil {
+		return user, role_name, []string{}, err
+	}
+
+	permissions, err := repo.Permissions(UserUUID)
+	if err != nil {
+		return user, role_name, []string{}, err
+	}
+
+	return user, role_name, permissions, nil
+}
+
+func (repo *AuthRepo) IsOwner(resource string, ResourceUUID string, UserUUID string) (bool, error) {
+	owns, ok := owners[resource]
+	if !ok {
+		return false, ErrNoOwner
+	}
+
+	return owns(repo.db, ResourceUUID, UserUUID)
+}
+
+// StartImpersonation records the impersonation session a token is issued for, ID being its jti.
+func (repo *AuthRepo) StartImpersonation(ID string, UserUUID string, ImpersonatorUUID string, ExpiresAt time.Time) error {
+	query := `INSERT INTO impersonations (uuid, user_uuid, impersonator_uuid, expires_at, created_at) VALUES(?,?,?,?,?)`
+	_, err := repo.db.ExecContext(context.Background(), query, ID, UserUUID, ImpersonatorUUID, ExpiresAt, time.Now())
+	return err
+}
+
+// StopImpersonation ends an impersonation session, telling whether it was still going on.
+func (repo *AuthRepo) StopImpersonation(ID string) (bool, error) {
+	res, err := repo.db.ExecContext(context.Background(), `UPDATE impersonations SET ended_at = ? WHERE uuid = ? AND ended_at IS NULL`, time.Now(), ID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+// ImpersonationActive tells whether an impersonation session exists and was not ended.
+func (repo *AuthRepo) ImpersonationActive(ID string) (bool, error) {
+	return exists(repo.db, `SELECT count(*) FROM impersonations WHERE uuid = ? AND ended_at IS NULL AND expires_at > ?`, ID, time.Now())
+}
+
+// exists tells whether query counts any row.
+func exists(db *database.DB, query string, args ...interface{}) (bool, error) {
+	var count int
+	if err := db.QueryRowContext(context.Background(), query, args...).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 func NewAuthRepo(db *database.DB) AuthRepository {
 	return &AuthRepo{db}
 }
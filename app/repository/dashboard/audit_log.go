@@ -0,0 +1,28 @@
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/google/uuid"
+)
+
+type AuditLogRepository interface {
+	Store(request *model.StoreAuditLog) error
+}
+
+type AuditLogRepo struct {
+	db *database.DB
+}
+
+func (repo *AuditLogRepo) Store(request *model.StoreAuditLog) error {
+	query := `INSERT INTO audit_logs (uuid, user_uuid, impersonator_uuid, method, path, status, created_at) VALUES(?,?,?,?,?,?,?)`
+	_, err := repo.db.ExecContext(context.Background(), query, uuid.New(), request.UserUUID, request.ImpersonatorUUID, request.Method, request.Path, request.Status, time.Now())
+	return err
+}
+
+func NewAuditLogRepo(db *database.DB) AuditLogRepository {
+	return &AuditLogRepo{db}
+}
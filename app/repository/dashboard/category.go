@@ -0,0 +1,367 @@
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/google/uuid"
+	"github.com/gosimple/slug"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryTooDeep  = fmt.Errorf("categories can't nest more than %d levels deep", model.CategoryMaxDepth)
+	ErrCategoryCycle    = errors.New("a category can't be moved under itself or one of its descendants")
+)
+
+// categoryRecursionLimit stops the recursive queries below on rows that somehow form a loop.
+const categoryRecursionLimit = 64
+
+type CategoryRepository interface {
+	Index(limit int, offset uint, search string, sort_by string, sort string) ([]model.Category, int, error)
+	Show(UUID string) (model.CategoryShow, error)
+	Store(request *model.StoreCategory) (model.Category, error)
+	Update(UUID string, request *model.UpdateCategory) (model.Category, error)
+	Destroy(UUID string) (model.Category, error)
+	GetSlug(Name string, UUID *string) string
+}
+
+type CategoryRepo struct {
+	db *database.DB
+}
+
+func (repo *CategoryRepo) Index(limit int, offset uint, search string, sort_by string, sort string) ([]model.Category, int, error) {
+	_select := "uuid, parent_uuid, name, slug, is_active, created_at, updated_at, deleted_at"
+	_conditions := database.Search([]string{"name"}, search, "categories.deleted_at")
+	_order := database.OrderBy(sort_by, sort)
+	_limit := database.Limit(limit, offset)
+
+	count_query := fmt.Sprintf(`SELECT count(*) FROM categories %s`, _conditions)
+	var count int
+	_ = repo.db.QueryRow(count_query).Scan(&count)
+
+	query := fmt.Sprintf(`SELECT %s FROM categories %s %s %s`, _select, _conditions, _order, _limit)
+
+	rows, err := repo.db.QueryContext(context.Background(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	items := []model.Category{}
+	for rows.Next() {
+		var i model.Category
+		if err := rows.Scan(
+			&i.UUID,
+			&i.ParentUUID,
+			&i.Name,
+			&i.Slug,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+func (repo *CategoryRepo) Show(UUID string) (model.CategoryShow, error) {
+	var category model.CategoryShow
+	query := "SELECT uuid, parent_uuid, name, slug, is_active, created_at, updated_at, deleted_at FROM categories WHERE uuid = ? AND categories.deleted_at IS NULL LIMIT 1"
+	err := repo.db.QueryRowContext(context.Background(), query, UUID).Scan(
+		&category.UUID,
+		&category.ParentUUID,
+		&category.Name,
+		&category.Slug,
+		&category.IsActive,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+		&category.DeletedAt,
+	)
+	if err != nil {
+		return model.CategoryShow{}, err
+	}
+
+	category.Depth, err = categoryDepth(repo.db, UUID)
+	if err != nil {
+		return model.CategoryShow{}, err
+	}
+
+	return category, nil
+}
+
+func (repo *CategoryRepo) Store(request *model.StoreCategory) (model.Category, error) {
+	if request.ParentUUID != "" {
+		depth, err := categoryDepth(repo.db, request.ParentUUID)
+		if err != nil {
+			return model.Category{}, err
+		}
+		if depth+1 > model.CategoryMaxDepth {
+			return model.Category{}, ErrCategoryTooDeep
+		}
+	}
+
+	ID := uuid.New().String()
+	query := `INSERT INTO categories (uuid, parent_uuid, name, slug, is_active, created_at) VALUES(?,?,?,?,?,?)`
+	_, err := repo.db.ExecContext(context.Background(), query, ID, nullableUUID(request.ParentUUID), request.Name, request.Slug, request.IsActive, time.Now())
+	if err != nil {
+		return model.Category{}, err
+	}
+
+	return findCategory(repo.db, ID)
+}
+
+func (repo *CategoryRepo) Update(UUID string, request *model.UpdateCategory) (model.Category, error) {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return model.Category{}, err
+	}
+	defer tx.Rollback()
+
+	if err := lockCategories(tx, UUID, request.ParentUUID); err != nil {
+		return model.Category{}, err
+	}
+
+	subtree, height, err := categorySubtree(tx, UUID)
+	if err != nil {
+		return model.Category{}, err
+	}
+	if len(subtree) == 0 {
+		return model.Category{}, sql.ErrNoRows
+	}
+
+	// The whole subtree moves along, so its height counts against the depth limit.
+	if request.ParentUUID != "" {
+		for _, descendant := range subtree {
+			if descendant == request.ParentUUID {
+				return model.Category{}, ErrCategoryCycle
+			}
+		}
+
+		depth, err := categoryDepth(tx, request.ParentUUID)
+		if err != nil {
+			return model.Category{}, err
+		}
+		if depth+height > model.CategoryMaxDepth {
+			return model.Category{}, ErrCategoryTooDeep
+		}
+	}
+
+	query := `UPDATE categories SET parent_uuid = ?, name = ?, slug = ?, is_active = ?, updated_at = ? WHERE uuid = ?`
+	_, err = tx.ExecContext(context.Background(), query, nullableUUID(request.ParentUUID), request.Name, request.Slug, request.IsActive, time.Now(), UUID)
+	if err != nil {
+		return model.Category{}, err
+	}
+
+	category, err := findCategory(tx, UUID)
+	if err != nil {
+		return model.Category{}, err
+	}
+
+	return category, tx.Commit()
+}
+
+// Destroy soft deletes a category. Its children move up to its parent and its posts are left
+// without a category.
+func (repo *CategoryRepo) Destroy(UUID string) (model.Category, error) {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return model.Category{}, err
+	}
+	defer tx.Rollback()
+
+	var parent_uuid *string
+	err = tx.QueryRowContext(context.Background(), `SELECT parent_uuid FROM categories WHERE uuid = ? AND deleted_at IS NULL FOR UPDATE`, UUID).Scan(&parent_uuid)
+	if err != nil {
+		return model.Category{}, err
+	}
+
+	if _, err := tx.ExecContext(context.Background(), `UPDATE categories SET updated_at = ?, deleted_at = ? WHERE uuid = ?`, time.Now(), time.Now(), UUID); err != nil {
+		return model.Category{}, err
+	}
+
+	if _, err := tx.ExecContext(context.Background(), `UPDATE categories SET parent_uuid = ? WHERE parent_uuid = ?`, parent_uuid, UUID); err != nil {
+		return model.Category{}, err
+	}
+
+	if _, err := tx.ExecContext(context.Background(), `UPDATE posts SET category_uuid = NULL, updated_at = updated_at WHERE category_uuid = ?`, UUID); err != nil {
+		return model.Category{}, err
+	}
+
+	category, err := findCategory(tx, UUID)
+	if err != nil {
+		return model.Category{}, err
+	}
+
+	return category, tx.Commit()
+}
+
+func (repo *CategoryRepo) GetSlug(Name string, UUID *string) string {
+	first_slug := slug.Make(Name)
+	new_slug := first_slug
+
+	for count := 1; ; count++ {
+		var slug_check string
+		var err error
+		if UUID == nil {
+			err = repo.db.QueryRowContext(context.Background(), `SELECT slug FROM categories WHERE slug = ? LIMIT 1`, new_slug).Scan(&slug_check)
+		} else {
+			err = repo.db.QueryRowContext(context.Background(), `SELECT slug FROM categories WHERE slug = ? AND uuid != ? LIMIT 1`, new_slug, UUID).Scan(&slug_check)
+		}
+		if err != nil {
+			return new_slug
+		}
+		new_slug = first_slug + "-" + strconv.Itoa(count)
+	}
+}
+
+func findCategory(db sqlx.QueryerContext, UUID string) (model.Category, error) {
+	var category model.Category
+	err := db.QueryRowxContext(context.Background(), "SELECT uuid, parent_uuid, name, slug, is_active, created_at, updated_at, deleted_at FROM categories WHERE uuid = ?", UUID).Scan(
+		&category.UUID,
+		&category.ParentUUID,
+		&category.Name,
+		&category.Slug,
+		&category.IsActive,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+		&category.DeletedAt,
+	)
+	if err != nil {
+		return model.Category{}, err
+	}
+
+	return category, nil
+}
+
+// lockCategories locks a category and its new parent until the transaction ends, so two moves can't
+// both pass the cycle and depth checks and then make a cycle or a tree too deep together. The rows
+// are locked in UUID order so concurrent moves don't deadlock.
+func lockCategories(tx *sqlx.Tx, UUID string, ParentUUID string) error {
+	uuids := []string{UUID}
+	if ParentUUID != "" && ParentUUID != UUID {
+		uuids = append(uuids, ParentUUID)
+	}
+
+	query, args, err := sqlx.In(`SELECT uuid FROM categories WHERE uuid IN (?) AND deleted_at IS NULL ORDER BY uuid FOR UPDATE`, uuids)
+	if err != nil {
+		return err
+	}
+
+	var locked []string
+	if err := tx.SelectContext(context.Background(), &locked, tx.Rebind(query), args...); err != nil {
+		return err
+	}
+	for _, found := range locked {
+		if found == UUID {
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// categoryDepth returns the level of a category, 1 for a root category, walking up its ancestors.
+func categoryDepth(db sqlx.QueryerContext, UUID string) (int, error) {
+	query := `
+	WITH RECURSIVE ancestors (uuid, parent_uuid, depth) AS (
+		SELECT uuid, parent_uuid, 1 FROM categories WHERE uuid = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT categories.uuid, categories.parent_uuid, ancestors.depth + 1
+		FROM categories JOIN ancestors ON categories.uuid = ancestors.parent_uuid
+		WHERE ancestors.depth < ?
+	)
+	SELECT MAX(depth) FROM ancestors
+	`
+
+	var depth sql.NullInt64
+	if err := db.QueryRowxContext(context.Background(), query, UUID, categoryRecursionLimit).Scan(&depth); err != nil {
+		return 0, err
+	}
+	if !depth.Valid {
+		return 0, ErrCategoryNotFound
+	}
+
+	return int(depth.Int64), nil
+}
+
+// categorySubtree returns a category with all its descendants and the height of that subtree,
+// 1 for a category without children.
+func categorySubtree(db sqlx.QueryerContext, UUID string) ([]string, int, error) {
+	query := `
+	WITH RECURSIVE subtree (uuid, depth) AS (
+		SELECT uuid, 1 FROM categories WHERE uuid = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT categories.uuid, subtree.depth + 1
+		FROM categories JOIN subtree ON categories.parent_uuid = subtree.uuid
+		WHERE categories.deleted_at IS NULL AND subtree.depth < ?
+	)
+	SELECT uuid, depth FROM subtree
+	`
+
+	rows, err := db.QueryxContext(context.Background(), query, UUID, categoryRecursionLimit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	uuids := []string{}
+	height := 0
+	for rows.Next() {
+		var descendant string
+		var depth int
+		if err := rows.Scan(&descendant, &depth); err != nil {
+			return nil, 0, err
+		}
+		uuids = append(uuids, descendant)
+		if depth > height {
+			height = depth
+		}
+	}
+
+	return uuids, height, rows.Err()
+}
+
+// checkCategory makes sure a post's primary category exists. An empty UUID means no category.
+func checkCategory(db sqlx.QueryerContext, UUID string) error {
+	if UUID == "" {
+		return nil
+	}
+
+	var exists int
+	err := db.QueryRowxContext(context.Background(), `SELECT 1 FROM categories WHERE uuid = ? AND deleted_at IS NULL LIMIT 1`, UUID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return ErrCategoryNotFound
+	}
+	return err
+}
+
+// nullableUUID stores an empty UUID as NULL.
+func nullableUUID(UUID string) sql.NullString {
+	return sql.NullString{String: UUID, Valid: UUID != ""}
+}
+
+// nullableJSON stores an empty JSON document as NULL.
+func nullableJSON(document string) sql.NullString {
+	return sql.NullString{String: document, Valid: document != ""}
+}
+
+func NewCategoryRepo(db *database.DB) CategoryRepository {
+	return &CategoryRepo{db}
+}
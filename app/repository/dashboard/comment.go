@@ -0,0 +1,242 @@
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrInvalidCommentAction is returned when a bulk moderation action is unknown.
+var ErrInvalidCommentAction = errors.New("invalid comment action")
+
+const commentSelect = `
+	comments.uuid,
+	comments.post_uuid,
+	comments.root_uuid,
+	comments.parent_uuid,
+	comments.user_uuid,
+	comments.guest_name,
+	comments.guest_email,
+	comments.content,
+	comments.status,
+	comments.created_at,
+	comments.updated_at,
+	comments.deleted_at,
+	IF(
+		users.uuid IS NULL,
+		NULL,
+		JSON_OBJECT(
+			'uuid', users.uuid,
+			'name', users.name,
+			'username', users.username,
+			'email', users.email
+		)
+	) AS user,
+	IF(
+		posts.uuid IS NULL,
+		NULL,
+		JSON_OBJECT(
+			'uuid', posts.uuid,
+			'title', posts.title,
+			'slug', posts.slug
+		)
+	) AS post`
+
+const commentJoin = `comments LEFT JOIN users ON users.uuid = comments.user_uuid LEFT JOIN posts ON posts.uuid = comments.post_uuid`
+
+type CommentRepository interface {
+	Index(limit int, offset uint, search string, sort_by string, sort string, status string) ([]model.Comment, int, error)
+	Show(UUID string) (model.Comment, error)
+	Update(UUID string, request *model.UpdateComment) (model.Comment, error)
+	Moderate(UUID string, status string) (model.Comment, error)
+	Destroy(UUID string) (model.Comment, error)
+	Bulk(UUIDs []string, action string) (int64, error)
+}
+
+type CommentRepo struct {
+	db *database.DB
+}
+
+func (repo *CommentRepo) Index(limit int, offset uint, search string, sort_by string, sort string, status string) ([]model.Comment, int, error) {
+	_conditions := database.Search([]string{"comments.content", "comments.guest_name", "comments.guest_email", "users.name", "posts.title"}, search, "comments.deleted_at")
+	_order := ""
+	if sort_by == "id" {
+		_order = database.OrderBy("comments.id", sort)
+	} else {
+		_order = database.OrderBy(sort_by, sort)
+	}
+	_limit := database.Limit(limit, offset)
+
+	args := []interface{}{}
+	if status != "" {
+		_conditions += " AND comments.status = ?"
+		args = append(args, status)
+	}
+
+	count_query := fmt.Sprintf(`SELECT count(*) FROM %s %s`, commentJoin, _conditions)
+	var count int
+	_ = repo.db.QueryRow(count_query, args...).Scan(&count)
+
+	query := fmt.Sprintf(`SELECT %s FROM %s %s %s %s`, commentSelect, commentJoin, _conditions, _order, _limit)
+
+	rows, err := repo.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	items := []model.Comment{}
+	for rows.Next() {
+		var i model.Comment
+		err := rows.Scan(
+			&i.UUID,
+			&i.PostUUID,
+			&i.RootUUID,
+			&i.ParentUUID,
+			&i.UserUUID,
+			&i.GuestName,
+			&i.GuestEmail,
+			&i.Content,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.User,
+			&i.Post,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+func (repo *CommentRepo) Show(UUID string) (model.Comment, error) {
+	return findComment(repo.db, UUID, false)
+}
+
+func (repo *CommentRepo) Update(UUID string, request *model.UpdateComment) (model.Comment, error) {
+	query := `UPDATE comments SET content = ?, updated_at = ? WHERE uuid = ? AND deleted_at IS NULL`
+	if err := execComment(repo.db, query, request.Content, time.Now(), UUID); err != nil {
+		return model.Comment{}, err
+	}
+
+	return findComment(repo.db, UUID, false)
+}
+
+func (repo *CommentRepo) Moderate(UUID string, status string) (model.Comment, error) {
+	query := `UPDATE comments SET status = ?, updated_at = ? WHERE uuid = ? AND deleted_at IS NULL`
+	if err := execComment(repo.db, query, status, time.Now(), UUID); err != nil {
+		return model.Comment{}, err
+	}
+
+	return findComment(repo.db, UUID, false)
+}
+
+func (repo *CommentRepo) Destroy(UUID string) (model.Comment, error) {
+	query := `UPDATE comments SET updated_at = ?, deleted_at = ? WHERE uuid = ? AND deleted_at IS NULL`
+	if err := execComment(repo.db, query, time.Now(), time.Now(), UUID); err != nil {
+		return model.Comment{}, err
+	}
+
+	return findComment(repo.db, UUID, true)
+}
+
+// Bulk approves, rejects or deletes many comments at once and returns how many were changed.
+func (repo *CommentRepo) Bulk(UUIDs []string, action string) (int64, error) {
+	if len(UUIDs) == 0 {
+		return 0, nil
+	}
+
+	var query string
+	var args []interface{}
+	switch action {
+	case "approve":
+		query = `UPDATE comments SET status = ?, updated_at = ? WHERE uuid IN (?) AND deleted_at IS NULL`
+		args = []interface{}{model.CommentStatusApproved, time.Now(), UUIDs}
+	case "reject":
+		query = `UPDATE comments SET status = ?, updated_at = ? WHERE uuid IN (?) AND deleted_at IS NULL`
+		args = []interface{}{model.CommentStatusRejected, time.Now(), UUIDs}
+	case "delete":
+		query = `UPDATE comments SET updated_at = ?, deleted_at = ? WHERE uuid IN (?) AND deleted_at IS NULL`
+		args = []interface{}{time.Now(), time.Now(), UUIDs}
+	default:
+		return 0, ErrInvalidCommentAction
+	}
+
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := repo.db.ExecContext(context.Background(), query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// execComment runs a single-comment update and reports sql.ErrNoRows when nothing matched.
+func execComment(db *database.DB, query string, args ...interface{}) error {
+	result, err := db.ExecContext(context.Background(), query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func findComment(db *database.DB, UUID string, withDeleted bool) (model.Comment, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE comments.uuid = ?`, commentSelect, commentJoin)
+	if !withDeleted {
+		query += " AND comments.deleted_at IS NULL"
+	}
+
+	var comment model.Comment
+	err := db.QueryRowContext(context.Background(), query, UUID).Scan(
+		&comment.UUID,
+		&comment.PostUUID,
+		&comment.RootUUID,
+		&comment.ParentUUID,
+		&comment.UserUUID,
+		&comment.GuestName,
+		&comment.GuestEmail,
+		&comment.Content,
+		&comment.Status,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.DeletedAt,
+		&comment.User,
+		&comment.Post,
+	)
+
+	return comment, err
+}
+
+func NewCommentRepo(db *database.DB) CommentRepository {
+	return &CommentRepo{db}
+}
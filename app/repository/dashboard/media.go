@@ -0,0 +1,218 @@
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrMediaNotFound = errors.New("media not found")
+	ErrMediaInUse    = errors.New("media is used by a post")
+	ErrMediaPrivate  = errors.New("private media can't be a post thumbnail")
+)
+
+// mediaUsage counts the posts, deleted ones included as they can come back, using a media row as thumbnail.
+const mediaUsage = `(SELECT COUNT(*) FROM posts WHERE posts.thumbnail_media_uuid = media.uuid OR posts.thumbnail = media.url)`
+
+// mediaReferenced tells whether a post or a post revision refers to a media row, by UUID or by the URL
+// thumbnails from before the media library and revisions keep.
+const mediaReferenced = `(EXISTS (SELECT 1 FROM posts WHERE posts.thumbnail_media_uuid = media.uuid OR posts.thumbnail = media.url)
+	OR EXISTS (SELECT 1 FROM post_revisions WHERE post_revisions.thumbnail = media.url))`
+
+const mediaSelect = `media.uuid, media.user_uuid, media.storage_key, media.url, media.filename, media.content_type, media.size, media.width, media.height, media.variants, media.alt_text, media.caption, media.origin, media.is_private, ` + mediaUsage + ` AS usage_count, media.created_at, media.updated_at`
+
+type MediaRepository interface {
+	Index(limit int, offset uint, search string, content_type string, sort_by string, sort string) ([]model.Media, int, error)
+	Show(UUID string) (model.Media, error)
+	Store(request *model.StoreMedia) (model.Media, error)
+	Update(UUID string, request *model.UpdateMedia) (model.Media, error)
+	Destroy(UUID string) (model.Media, error)
+	All() ([]model.Media, error)
+	SetVariants(UUID string, variants string) error
+	Unused(origin string, before time.Time) ([]model.Media, error)
+	ReferencedURLs() ([]string, error)
+}
+
+type MediaRepo struct {
+	db *database.DB
+}
+
+// Index lists media, newest first by default. search matches the filename, alt text and caption,
+// content_type a type such as image/png or a prefix such as image/.
+func (repo *MediaRepo) Index(limit int, offset uint, search string, content_type string, sort_by string, sort string) ([]model.Media, int, error) {
+	_conditions := "WHERE 1 = 1"
+	args := []interface{}{}
+	if search != "" {
+		_conditions += " AND (media.filename LIKE ? OR media.alt_text LIKE ? OR media.caption LIKE ?)"
+		like := "%" + search + "%"
+		args = append(args, like, like, like)
+	}
+	if content_type != "" {
+		_conditions += " AND media.content_type LIKE ?"
+		args = append(args, strings.TrimSuffix(content_type, "*")+"%")
+	}
+	_order := database.OrderBy(sort_by, sort)
+	_limit := database.Limit(limit, offset)
+
+	count_query := fmt.Sprintf(`SELECT count(*) FROM media %s`, _conditions)
+	var count int
+	_ = repo.db.QueryRow(count_query, args...).Scan(&count)
+
+	query := fmt.Sprintf(`SELECT %s FROM media %s %s %s`, mediaSelect, _conditions, _order, _limit)
+
+	items := []model.Media{}
+	if err := repo.db.SelectContext(context.Background(), &items, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+func (repo *MediaRepo) Show(UUID string) (model.Media, error) {
+	return findMedia(repo.db, UUID)
+}
+
+func (repo *MediaRepo) Store(request *model.StoreMedia) (model.Media, error) {
+	ID := uuid.New().String()
+	query := `INSERT INTO media (uuid, user_uuid, storage_key, url, filename, content_type, size, width, height, variants, alt_text, caption, origin, is_private, created_at) VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+	_, err := repo.db.ExecContext(context.Background(), query, ID, nullableUUID(request.UserUUID), request.StorageKey, request.URL, request.Filename, request.ContentType, request.Size, request.Width, request.Height, nullableJSON(request.Variants), request.AltText, sql.NullString{String: request.Caption, Valid: request.Caption != ""}, request.Origin, request.IsPrivate, time.Now())
+	if err != nil {
+		return model.Media{}, err
+	}
+
+	return findMedia(repo.db, ID)
+}
+
+func (repo *MediaRepo) Update(UUID string, request *model.UpdateMedia) (model.Media, error) {
+	if _, err := findMedia(repo.db, UUID); err != nil {
+		return model.Media{}, err
+	}
+
+	query := `UPDATE media SET alt_text = ?, caption = ?, updated_at = ? WHERE uuid = ?`
+	_, err := repo.db.ExecContext(context.Background(), query, request.AltText, sql.NullString{String: request.Caption, Valid: request.Caption != ""}, time.Now(), UUID)
+	if err != nil {
+		return model.Media{}, err
+	}
+
+	return findMedia(repo.db, UUID)
+}
+
+// Destroy deletes a media row that no post or post revision refers to, returning it so its files can be removed from storage.
+func (repo *MediaRepo) Destroy(UUID string) (model.Media, error) {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return model.Media{}, err
+	}
+	defer tx.Rollback()
+
+	var referenced bool
+	if err := tx.QueryRowContext(context.Background(), `SELECT `+mediaReferenced+` FROM media WHERE uuid = ? FOR UPDATE`, UUID).Scan(&referenced); err != nil {
+		return model.Media{}, err
+	}
+	if referenced {
+		return model.Media{}, ErrMediaInUse
+	}
+
+	media, err := findMedia(tx, UUID)
+	if err != nil {
+		return model.Media{}, err
+	}
+
+	if _, err := tx.ExecContext(context.Background(), `DELETE FROM media WHERE uuid = ?`, UUID); err != nil {
+		return model.Media{}, err
+	}
+
+	return media, tx.Commit()
+}
+
+// All lists every media row, for regenerating variants.
+func (repo *MediaRepo) All() ([]model.Media, error) {
+	items := []model.Media{}
+	err := repo.db.SelectContext(context.Background(), &items, fmt.Sprintf(`SELECT %s FROM media ORDER BY media.id`, mediaSelect))
+	return items, err
+}
+
+// SetVariants replaces the variants of a media row and of the posts using it as thumbnail.
+func (repo *MediaRepo) SetVariants(UUID string, variants string) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(context.Background(), `UPDATE media SET variants = ?, updated_at = updated_at WHERE uuid = ?`, nullableJSON(variants), UUID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(context.Background(), `UPDATE posts SET thumbnail_variants = ?, updated_at = updated_at WHERE thumbnail_media_uuid = ?`, nullableJSON(variants), UUID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Unused lists media of an origin created before a time that neither a post nor a post revision
+// refers to any more.
+func (repo *MediaRepo) Unused(origin string, before time.Time) ([]model.Media, error) {
+	query := fmt.Sprintf(`SELECT %s FROM media
+	WHERE media.origin = ? AND media.created_at < ?
+	AND NOT %s`, mediaSelect, mediaReferenced)
+
+	items := []model.Media{}
+	err := repo.db.SelectContext(context.Background(), &items, query, origin, before)
+	return items, err
+}
+
+// ReferencedURLs lists every file URL the database refers to: media with their variants, and post
+// and revision thumbnails from before the media library.
+func (repo *MediaRepo) ReferencedURLs() ([]string, error) {
+	urls := []string{}
+	err := repo.db.SelectContext(context.Background(), &urls, `
+	SELECT url FROM media
+	UNION SELECT CAST(variants AS CHAR) FROM media WHERE variants IS NOT NULL
+	UNION SELECT thumbnail FROM posts WHERE thumbnail IS NOT NULL AND thumbnail != ''
+	UNION SELECT CAST(thumbnail_variants AS CHAR) FROM posts WHERE thumbnail_variants IS NOT NULL
+	UNION SELECT thumbnail FROM post_revisions WHERE thumbnail IS NOT NULL AND thumbnail != ''`)
+	return urls, err
+}
+
+func findMedia(db sqlx.QueryerContext, UUID string) (model.Media, error) {
+	var media model.Media
+	err := sqlx.GetContext(context.Background(), db, &media, fmt.Sprintf(`SELECT %s FROM media WHERE media.uuid = ?`, mediaSelect), UUID)
+	return media, err
+}
+
+// attachThumbnail makes a media row the thumbnail of a post, copying its URL and variants onto the post.
+func attachThumbnail(tx *sqlx.Tx, PostUUID string, MediaUUID string) error {
+	media, err := findMedia(tx, MediaUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrMediaNotFound
+		}
+		return err
+	}
+	if media.IsPrivate {
+		return ErrMediaPrivate
+	}
+
+	variants := ""
+	if media.Variants != nil {
+		variants = string(*media.Variants)
+	}
+
+	query := `UPDATE posts SET thumbnail = ?, thumbnail_variants = ?, thumbnail_media_uuid = ? WHERE uuid = ?`
+	_, err = tx.ExecContext(context.Background(), query, media.URL, nullableJSON(variants), MediaUUID, PostUUID)
+	return err
+}
+
+func NewMediaRepo(db *database.DB) MediaRepository {
+	return &MediaRepo{db}
+}
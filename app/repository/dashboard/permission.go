@@ -8,6 +8,7 @@ import (
 	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 )
 
 type PermissionRepository interface {
@@ -16,6 +17,7 @@ type PermissionRepository interface {
 	Store(model *model.StorePermission) (model.Permission, error)
 	Update(UUID string, request *model.UpdatePermission) (model.Permission, error)
 	Destroy(UUID string) (model.Permission, error)
+	Names(UUIDs []string) ([]string, error)
 }
 
 type PermissionRepo struct {
@@ -128,6 +130,38 @@ func (repo *PermissionRepo) Destroy(UUID string) (model.Permission, error) {
 	return Tag, err
 }
 
+// Names resolves permission UUIDs to their names, skipping unknown or deleted ones.
+func (repo *PermissionRepo) Names(UUIDs []string) ([]string, error) {
+	names := []string{}
+	if len(UUIDs) == 0 {
+		return names, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT name FROM permissions WHERE uuid IN (?) AND deleted_at IS NULL`, UUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
 func NewPermissionRepo(db *database.DB) PermissionRepository {
 	return &PermissionRepo{db}
 }
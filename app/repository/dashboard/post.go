@@ -3,41 +3,110 @@ package dashboard
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
 
 	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/app/repository"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/highlight"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/markup"
 	"github.com/google/uuid"
 	"github.com/gosimple/slug"
+	"github.com/jmoiron/sqlx"
 )
 
+// ErrTagNotFound is returned when a post references a tag that does not exist.
+var ErrTagNotFound = errors.New("tag not found")
+
+// ErrInvalidTransition is returned when a post can't move from its current status to the requested one.
+var ErrInvalidTransition = errors.New("invalid post status transition")
+
+// ErrInvalidPublishedAt is returned when published_at doesn't fit the requested status.
+var ErrInvalidPublishedAt = errors.New("scheduled posts need a published_at in the future, published posts can't have one")
+
+// postTransitions lists, for each status, the statuses a post may move to and the permission each move requires.
+var postTransitions = map[string]map[string]string{
+	model.PostStatusDraft: {
+		model.PostStatusInReview:  "post-update",
+		model.PostStatusScheduled: "post-publish",
+		model.PostStatusPublished: "post-publish",
+	},
+	model.PostStatusInReview: {
+		model.PostStatusDraft:     "post-update",
+		model.PostStatusScheduled: "post-publish",
+		model.PostStatusPublished: "post-publish",
+	},
+	model.PostStatusScheduled: {
+		model.PostStatusDraft:     "post-publish",
+		model.PostStatusPublished: "post-publish",
+	},
+	model.PostStatusPublished: {
+		model.PostStatusDraft:    "post-publish",
+		model.PostStatusArchived: "post-publish",
+	},
+	model.PostStatusArchived: {
+		model.PostStatusDraft: "post-publish",
+	},
+}
+
+// PostTransitionPermission returns the permission needed to move a post from one status to another,
+// or ErrInvalidTransition when the move isn't allowed at all.
+func PostTransitionPermission(from string, to string) (string, error) {
+	permission, ok := postTransitions[from][to]
+	if !ok {
+		return "", ErrInvalidTransition
+	}
+	return permission, nil
+}
+
 type PostRepository interface {
-	Index(limit int, offset uint, search string, sort_by string, sort string) ([]model.Post, int, error)
+	Index(limit int, offset uint, search string, search_mode string, sort_by string, sort string) ([]model.Post, int, error)
 	Show(UUID string) (model.PostShow, error)
 	Store(model *model.StorePost) (model.Post, error)
 	Update(UUID string, request *model.UpdatePost) (model.Post, error)
 	Destroy(UUID string) (model.Post, error)
+	UpdateStatus(UUID string, status string, publishedAt *time.Time) (model.Post, error)
+	PublishScheduled() (int64, error)
 	GetSlug(Title string, UUID *string) string
+	CheckSlug(Slug string, UUID string) error
+	RenderAll() (int, error)
+	Thumbnails() ([]model.PostThumbnail, error)
+	SetThumbnailVariants(UUID string, variants string) error
 }
 
 type PostRepo struct {
 	db *database.DB
 }
 
-func (repo *PostRepo) Index(limit int, offset uint, search string, sort_by string, sort string) ([]model.Post, int, error) {
-	_select := `
+func (repo *PostRepo) Index(limit int, offset uint, search string, search_mode string, sort_by string, sort string) ([]model.Post, int, error) {
+	match, fulltext := repo.db.FullText([]string{"title", "content", "keyword"}, search, search_mode)
+	relevance := "NULL"
+	if fulltext {
+		relevance = match
+	}
+
+	_select := fmt.Sprintf(`
 	posts.uuid,
     user_uuid,
-    tag_uuid,
+    posts.category_uuid,
     title,
     thumbnail,
+    posts.thumbnail_variants,
+    posts.thumbnail_media_uuid,
     content,
+    posts.content_format,
     keyword,
     posts.slug,
+    posts.slug_locked,
+    posts.status,
+    posts.published_at,
     posts.is_active,
     is_highlight,
+    posts.view_count,
     posts.created_at,
     posts.updated_at,
     IFNULL(
@@ -51,21 +120,25 @@ func (repo *PostRepo) Index(limit int, offset uint, search string, sort_by strin
         ),
         NULL
     ) AS user,
-    IFNULL(
-        JSON_OBJECT(
-            'uuid', tags.uuid,
-            'name', tags.name,
-            'slug', tags.slug,
-            'is_active', tags.is_active,
-            'created_at', tags.created_at,
-            'updated_at', tags.updated_at
-        ),
-        NULL
-    ) AS tag
-	`
-	_conditions := database.Search([]string{"title", "content", "users.name", "tags.name"}, search, "posts.deleted_at")
+    %s AS tags,
+    %s AS relevance
+	`, repository.PostTags, relevance)
+
+	// Terms shorter than the FULLTEXT minimum token size fall back to LIKE.
+	_conditions := ""
+	args := []interface{}{}
+	if fulltext {
+		condition, condition_args := repository.PostFullText(match, search)
+		_conditions = fmt.Sprintf(" WHERE %s AND posts.deleted_at IS NULL", condition)
+		args = append(args, condition_args...)
+	} else {
+		_conditions = database.Search([]string{"title", "content", "users.name", repository.PostTagNames}, search, "posts.deleted_at")
+	}
+
 	_order := ""
-	if sort_by == "id" {
+	if sort_by == "relevance" && fulltext {
+		_order = database.OrderBy("relevance", sort)
+	} else if sort_by == "id" || sort_by == "relevance" {
 		_order = database.OrderBy("posts.id", sort)
 	} else {
 		_order = database.OrderBy(sort_by, sort)
@@ -73,13 +146,17 @@ func (repo *PostRepo) Index(limit int, offset uint, search string, sort_by strin
 
 	_limit := database.Limit(limit, offset)
 
-	count_query := fmt.Sprintf(`SELECT count(*) FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid LEFT JOIN tags ON tags.uuid = posts.tag_uuid %s`, _conditions)
+	count_query := fmt.Sprintf(`SELECT count(*) FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid %s`, _conditions)
 	var count int
-	_ = repo.db.QueryRow(count_query).Scan(&count)
+	_ = repo.db.QueryRow(count_query, args...).Scan(&count)
 
-	query := fmt.Sprintf(`SELECT %s FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid LEFT JOIN tags ON tags.uuid = posts.tag_uuid %s %s %s`, _select, _conditions, _order, _limit)
+	query := fmt.Sprintf(`SELECT %s FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid %s %s %s`, _select, _conditions, _order, _limit)
+	// The relevance in the select list comes before the conditions.
+	if fulltext {
+		args = append([]interface{}{search}, args...)
+	}
 
-	rows, err := repo.db.QueryContext(context.Background(), query)
+	rows, err := repo.db.QueryContext(context.Background(), query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -90,23 +167,35 @@ func (repo *PostRepo) Index(limit int, offset uint, search string, sort_by strin
 		var i model.Post
 		err := rows.Scan(
 			&i.UUID,
-			&i.TagUUID,
 			&i.UserUUID,
+			&i.CategoryUUID,
 			&i.Title,
 			&i.Thumbnail,
+			&i.ThumbnailVariants,
+			&i.ThumbnailMediaUUID,
 			&i.Content,
+			&i.ContentFormat,
 			&i.Keyword,
 			&i.Slug,
+			&i.SlugLocked,
+			&i.Status,
+			&i.PublishedAt,
 			&i.IsActive,
 			&i.IsHighlight,
+			&i.ViewCount,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.User,
-			&i.Tag,
+			&i.Tags,
+			&i.Relevance,
 		)
 		if err != nil {
 			return nil, 0, err
 		}
+		if search != "" {
+			snippet := highlight.Snippet(i.Content, database.SearchTerms(search), 80)
+			i.Snippet = &snippet
+		}
 		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
@@ -121,18 +210,25 @@ func (repo *PostRepo) Index(limit int, offset uint, search string, sort_by strin
 
 func (repo *PostRepo) Show(UUID string) (model.PostShow, error) {
 	var post model.PostShow
-	query := `
+	query := fmt.Sprintf(`
 	SELECT 
 	posts.uuid,
     user_uuid,
-    tag_uuid,
+    posts.category_uuid,
     title,
     thumbnail,
+    posts.thumbnail_variants,
+    posts.thumbnail_media_uuid,
     content,
+    posts.content_format,
     keyword,
     posts.slug,
+    posts.slug_locked,
+    posts.status,
+    posts.published_at,
     posts.is_active,
     is_highlight,
+    posts.view_count,
     posts.created_at,
     posts.updated_at,
     IFNULL(
@@ -146,143 +242,152 @@ func (repo *PostRepo) Show(UUID string) (model.PostShow, error) {
         ),
         NULL
     ) AS user,
-    IFNULL(
-        JSON_OBJECT(
-            'uuid', tags.uuid,
-            'name', tags.name,
-            'slug', tags.slug,
-            'is_active', tags.is_active,
-            'created_at', tags.created_at,
-            'updated_at', tags.updated_at
-        ),
-        NULL
-    ) AS tag
-	FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid LEFT JOIN tags ON tags.uuid = posts.tag_uuid
+    %s
+	FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid
 	WHERE posts.uuid = ? AND posts.deleted_at IS NULL LIMIT 1
-	`
+	`, repository.PostTags)
 
 	err := repo.db.QueryRowContext(context.Background(), query, UUID).Scan(
 		&post.UUID,
-		&post.TagUUID,
 		&post.UserUUID,
+		&post.CategoryUUID,
 		&post.Title,
 		&post.Thumbnail,
+		&post.ThumbnailVariants,
+		&post.ThumbnailMediaUUID,
 		&post.Content,
+		&post.ContentFormat,
 		&post.Keyword,
 		&post.Slug,
+		&post.SlugLocked,
+		&post.Status,
+		&post.PublishedAt,
 		&post.IsActive,
 		&post.IsHighlight,
+		&post.ViewCount,
 		&post.CreatedAt,
 		&post.UpdatedAt,
 		&post.User,
-		&post.Tag,
+		&post.Tags,
 	)
 
 	return post, err
 }
 
 func (repo *PostRepo) Store(request *model.StorePost) (model.Post, error) {
-	query := `INSERT INTO posts (uuid, tag_uuid, user_uuid, title, thumbnail, content, keyword, slug, is_active, is_highlight, created_at) VALUES(?,?,?,?,?,?,?,?,?,?,?) 
-	RETURNING uuid, tag_uuid, user_uuid, title, thumbnail, content, keyword, slug, is_active, is_highlight, created_at`
-	var post model.Post
-	err := repo.db.QueryRowContext(context.Background(), query, uuid.New(), request.TagUUID, request.UserUUID, request.Title, request.Thumbnail, request.Content, request.Keyword, request.Slug, request.IsActive, request.IsHighlight, time.Now()).Scan(
-		&post.UUID,
-		&post.TagUUID,
-		&post.UserUUID,
-		&post.Title,
-		&post.Thumbnail,
-		&post.Content,
-		&post.Keyword,
-		&post.Slug,
-		&post.IsActive,
-		&post.IsHighlight,
-		&post.CreatedAt,
-	)
+	tx, err := repo.db.Beginx()
 	if err != nil {
 		return model.Post{}, err
 	}
-	return post, err
-}
+	defer tx.Rollback()
 
-func (repo *PostRepo) Update(ID string, request *model.UpdatePost) (model.Post, error) {
-	if request.Thumbnail == "" {
-		query := `UPDATE posts SET tag_uuid = ?, user_uuid = ?, title = ?, content = ?, keyword = ?, slug = ?, is_active = ?, is_highlight = ?, updated_at = ? WHERE uuid = ?`
-		result, err := repo.db.ExecContext(context.Background(), query, request.TagUUID, request.UserUUID, request.Title, request.Content, request.Keyword, request.Slug, request.IsActive, request.IsHighlight, time.Now(), ID)
-		if err != nil {
-			return model.Post{}, err
-		}
+	ID := uuid.New().String()
 
-		rowsAffected, err := result.RowsAffected()
-		if err != nil {
-			return model.Post{}, err
-		}
+	if err := checkCategory(tx, request.CategoryUUID); err != nil {
+		return model.Post{}, err
+	}
 
-		if rowsAffected == 0 {
-			return model.Post{}, sql.ErrNoRows
-		}
+	query := `INSERT INTO posts (uuid, user_uuid, category_uuid, title, thumbnail, content, keyword, slug, status, is_active, is_highlight, created_at) VALUES(?,?,?,?,?,?,?,?,?,?,?,?)`
+	_, err = tx.ExecContext(context.Background(), query, ID, request.UserUUID, nullableUUID(request.CategoryUUID), request.Title, "", request.Content, request.Keyword, request.Slug, model.PostStatusDraft, false, request.IsHighlight, time.Now())
+	if err != nil {
+		return model.Post{}, err
+	}
 
-		var post model.Post
-		err = repo.db.QueryRowContext(context.Background(), "SELECT uuid, tag_uuid, user_uuid, title, thumbnail, content, keyword, slug, is_active, is_highlight, created_at, updated_at FROM posts WHERE uuid = ?", ID).Scan(
-			&post.UUID,
-			&post.TagUUID,
-			&post.UserUUID,
-			&post.Title,
-			&post.Thumbnail,
-			&post.Content,
-			&post.Keyword,
-			&post.Slug,
-			&post.IsActive,
-			&post.IsHighlight,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-		)
-		if err != nil {
+	if request.ThumbnailMediaUUID != "" {
+		if err := attachThumbnail(tx, ID, request.ThumbnailMediaUUID); err != nil {
 			return model.Post{}, err
 		}
+	}
 
-		return post, nil
-	} else {
-		query := `UPDATE posts SET tag_uuid = ?, user_uuid = ?, title = ?, thumbnail = ?, content = ?, keyword = ?, slug = ?, is_active = ?, is_highlight = ?, updated_at = ? WHERE uuid = ?`
-		result, err := repo.db.ExecContext(context.Background(), query, request.TagUUID, request.UserUUID, request.Title, request.Thumbnail, request.Content, request.Keyword, request.Slug, request.IsActive, request.IsHighlight, time.Now(), ID)
-		if err != nil {
-			return model.Post{}, err
-		}
+	if request.ContentFormat == "" {
+		request.ContentFormat = markup.FormatHTML
+	}
+	if err := renderPostContent(tx, ID, request.Content, request.ContentFormat); err != nil {
+		return model.Post{}, err
+	}
 
-		rowsAffected, err := result.RowsAffected()
-		if err != nil {
-			return model.Post{}, err
-		}
+	if err := syncPostTags(tx, ID, request.TagUUIDs); err != nil {
+		return model.Post{}, err
+	}
 
-		if rowsAffected == 0 {
-			return model.Post{}, sql.ErrNoRows
-		}
+	if err := storePostRevision(tx, ID, request.EditorUUID); err != nil {
+		return model.Post{}, err
+	}
 
-		var post model.Post
-		err = repo.db.QueryRowContext(context.Background(), "SELECT uuid, tag_uuid, user_uuid, title, thumbnail, content, keyword, slug, is_active, is_highlight, created_at, updated_at FROM posts WHERE uuid = ?", ID).Scan(
-			&post.UUID,
-			&post.TagUUID,
-			&post.UserUUID,
-			&post.Title,
-			&post.Thumbnail,
-			&post.Content,
-			&post.Keyword,
-			&post.Slug,
-			&post.IsActive,
-			&post.IsHighlight,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-		)
-		if err != nil {
+	post, err := findPost(tx, ID, false)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	return post, tx.Commit()
+}
+
+func (repo *PostRepo) Update(ID string, request *model.UpdatePost) (model.Post, error) {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return model.Post{}, err
+	}
+	defer tx.Rollback()
+
+	var old_slug, content_format string
+	err = tx.QueryRowContext(context.Background(), `SELECT slug, content_format FROM posts WHERE uuid = ? AND deleted_at IS NULL FOR UPDATE`, ID).Scan(&old_slug, &content_format)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	// An empty ContentFormat keeps the post's format.
+	if request.ContentFormat == "" {
+		request.ContentFormat = content_format
+	}
+
+	if err := ensurePostRevision(tx, ID); err != nil {
+		return model.Post{}, err
+	}
+
+	if err := checkCategory(tx, request.CategoryUUID); err != nil {
+		return model.Post{}, err
+	}
+
+	// A nil SlugLocked leaves the lock as it is.
+	query := `UPDATE posts SET user_uuid = ?, category_uuid = ?, title = ?, content = ?, keyword = ?, slug = ?, slug_locked = COALESCE(?, slug_locked), is_highlight = ?, updated_at = ? WHERE uuid = ?`
+	_, err = tx.ExecContext(context.Background(), query, request.UserUUID, nullableUUID(request.CategoryUUID), request.Title, request.Content, request.Keyword, request.Slug, request.SlugLocked, request.IsHighlight, time.Now(), ID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	// An empty ThumbnailMediaUUID keeps the thumbnail.
+	if request.ThumbnailMediaUUID != "" {
+		if err := attachThumbnail(tx, ID, request.ThumbnailMediaUUID); err != nil {
 			return model.Post{}, err
 		}
+	}
 
-		return post, nil
+	if err := renderPostContent(tx, ID, request.Content, request.ContentFormat); err != nil {
+		return model.Post{}, err
+	}
+
+	if err := storeSlugRedirect(tx, SlugRedirectPost, old_slug, request.Slug, ID); err != nil {
+		return model.Post{}, err
+	}
+
+	if err := syncPostTags(tx, ID, request.TagUUIDs); err != nil {
+		return model.Post{}, err
+	}
+
+	if err := storePostRevision(tx, ID, request.EditorUUID); err != nil {
+		return model.Post{}, err
+	}
+
+	post, err := findPost(tx, ID, false)
+	if err != nil {
+		return model.Post{}, err
 	}
+
+	return post, tx.Commit()
 }
 
 func (repo *PostRepo) Destroy(UUID string) (model.Post, error) {
-	query := `UPDATE posts SET updated_at = ?, deleted_at = ? WHERE uuid = ? 
-	RETURNING uuid, tag_uuid, user_uuid, title, thumbnail, content, keyword, slug, is_active, is_highlight, created_at, updated_at, deleted_at`
+	query := `UPDATE posts SET updated_at = ?, deleted_at = ? WHERE uuid = ?`
 	result, err := repo.db.ExecContext(context.Background(), query, time.Now(), time.Now(), UUID)
 	if err != nil {
 		return model.Post{}, err
@@ -297,21 +402,142 @@ func (repo *PostRepo) Destroy(UUID string) (model.Post, error) {
 		return model.Post{}, sql.ErrNoRows
 	}
 
+	return findPost(repo.db, UUID, true)
+}
+
+// UpdateStatus moves a post to a new status. Scheduled posts keep the given future published_at,
+// published posts are stamped with the current time, archived posts keep their date and
+// drafts lose it.
+func (repo *PostRepo) UpdateStatus(UUID string, status string, publishedAt *time.Time) (model.Post, error) {
+	now := time.Now()
+
+	switch status {
+	case model.PostStatusScheduled:
+		if publishedAt == nil || !publishedAt.After(now) {
+			return model.Post{}, ErrInvalidPublishedAt
+		}
+	case model.PostStatusPublished:
+		if publishedAt != nil {
+			return model.Post{}, ErrInvalidPublishedAt
+		}
+		publishedAt = &now
+	default:
+		publishedAt = nil
+	}
+
+	var query string
+	var args []interface{}
+	if status == model.PostStatusArchived {
+		query = `UPDATE posts SET status = ?, is_active = ?, updated_at = ? WHERE uuid = ? AND deleted_at IS NULL`
+		args = []interface{}{status, false, now, UUID}
+	} else {
+		query = `UPDATE posts SET status = ?, published_at = ?, is_active = ?, updated_at = ? WHERE uuid = ? AND deleted_at IS NULL`
+		args = []interface{}{status, publishedAt, status == model.PostStatusPublished, now, UUID}
+	}
+
+	result, err := repo.db.ExecContext(context.Background(), query, args...)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if rowsAffected == 0 {
+		return model.Post{}, sql.ErrNoRows
+	}
+
+	return findPost(repo.db, UUID, false)
+}
+
+// PublishScheduled publishes every scheduled post whose published_at has passed and returns how many were published.
+func (repo *PostRepo) PublishScheduled() (int64, error) {
+	query := `UPDATE posts SET status = ?, is_active = ?, updated_at = ? WHERE status = ? AND published_at <= ? AND deleted_at IS NULL`
+	result, err := repo.db.ExecContext(context.Background(), query, model.PostStatusPublished, true, time.Now(), model.PostStatusScheduled, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// syncPostTags replaces the tags attached to a post, making sure every tag exists.
+func syncPostTags(tx *sqlx.Tx, postUUID string, tagUUIDs []string) error {
+	unique := map[string]struct{}{}
+	for _, tagUUID := range tagUUIDs {
+		if tagUUID != "" {
+			unique[tagUUID] = struct{}{}
+		}
+	}
+
+	if len(unique) > 0 {
+		ids := make([]string, 0, len(unique))
+		for tagUUID := range unique {
+			ids = append(ids, tagUUID)
+		}
+
+		check_query, args, err := sqlx.In(`SELECT count(*) FROM tags WHERE uuid IN (?) AND deleted_at IS NULL`, ids)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRowContext(context.Background(), check_query, args...).Scan(&count); err != nil {
+			return err
+		}
+
+		if count != len(ids) {
+			return ErrTagNotFound
+		}
+	}
+
+	_, err := tx.ExecContext(context.Background(), `DELETE FROM post_tags WHERE post_uuid = ?`, postUUID)
+	if err != nil {
+		return err
+	}
+
+	for tagUUID := range unique {
+		_, err := tx.ExecContext(context.Background(), `INSERT INTO post_tags (post_uuid, tag_uuid) VALUES (?, ?)`, postUUID, tagUUID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findPost reads a single post with its tags, optionally including soft-deleted rows.
+func findPost(db sqlx.QueryerContext, UUID string, withDeleted bool) (model.Post, error) {
+	query := fmt.Sprintf(`SELECT uuid, user_uuid, category_uuid, title, thumbnail, thumbnail_variants, thumbnail_media_uuid, content, content_format, keyword, slug, slug_locked, status, published_at, is_active, is_highlight, view_count, created_at, updated_at, deleted_at, %s AS tags FROM posts WHERE uuid = ?`, repository.PostTags)
+	if !withDeleted {
+		query += " AND deleted_at IS NULL"
+	}
+
 	var post model.Post
-	err = repo.db.QueryRowContext(context.Background(), "SELECT uuid, tag_uuid, user_uuid, title, thumbnail, content, keyword, slug, is_active, is_highlight, created_at, updated_at, deleted_at FROM posts WHERE uuid = ?", UUID).Scan(
+	err := db.QueryRowxContext(context.Background(), query, UUID).Scan(
 		&post.UUID,
-		&post.TagUUID,
 		&post.UserUUID,
+		&post.CategoryUUID,
 		&post.Title,
 		&post.Thumbnail,
+		&post.ThumbnailVariants,
+		&post.ThumbnailMediaUUID,
 		&post.Content,
+		&post.ContentFormat,
 		&post.Keyword,
 		&post.Slug,
+		&post.SlugLocked,
+		&post.Status,
+		&post.PublishedAt,
 		&post.IsActive,
 		&post.IsHighlight,
+		&post.ViewCount,
 		&post.CreatedAt,
 		&post.UpdatedAt,
 		&post.DeletedAt,
+		&post.Tags,
 	)
 	if err != nil {
 		return model.Post{}, err
@@ -321,68 +547,88 @@ func (repo *PostRepo) Destroy(UUID string) (model.Post, error) {
 }
 
 func (repo *PostRepo) GetSlug(Title string, UUID *string) string {
-	count := 0
+	return postSlug(repo.db, Title, UUID, "")
+}
+
+// postSlug returns the first slug made of Title that no other post has. In a transaction, a lock of
+// " FOR UPDATE" keeps other transactions from taking it before the commit.
+func postSlug(db sqlx.QueryerContext, Title string, UUID *string, lock string) string {
 	first_slug := slug.Make(Title)
-	var slug_check string
+	new_slug := first_slug
+
+	for count := 1; ; count++ {
+		var slug_check string
+		var err error
+		if UUID == nil {
+			err = db.QueryRowxContext(context.Background(), `SELECT slug FROM posts WHERE slug = ? LIMIT 1`+lock, new_slug).Scan(&slug_check)
+		} else {
+			err = db.QueryRowxContext(context.Background(), `SELECT slug FROM posts WHERE slug = ? AND uuid != ? LIMIT 1`+lock, new_slug, UUID).Scan(&slug_check)
+		}
+		if err != nil {
+			return new_slug
+		}
+		new_slug = first_slug + "-" + strconv.Itoa(count)
+	}
+}
 
-	query := ""
-	if UUID == nil {
-		query = `
-			SELECT 
-			slug
-			FROM posts 
-			WHERE posts.slug = ? LIMIT 1
-		`
+// CheckSlug validates a slug chosen by hand for the post.
+func (repo *PostRepo) CheckSlug(Slug string, UUID string) error {
+	return checkSlug(repo.db, "posts", Slug, UUID)
+}
 
-		err := repo.db.QueryRowContext(context.Background(), query, first_slug).Scan(&slug_check)
+// Thumbnails lists the thumbnail of every post that has one outside the media library, deleted
+// posts included, for regenerating their variants.
+func (repo *PostRepo) Thumbnails() ([]model.PostThumbnail, error) {
+	posts := []model.PostThumbnail{}
+	err := repo.db.SelectContext(context.Background(), &posts, `SELECT uuid, thumbnail, CAST(thumbnail_variants AS CHAR) AS thumbnail_variants FROM posts WHERE thumbnail IS NOT NULL AND thumbnail != '' AND thumbnail_media_uuid IS NULL`)
+	return posts, err
+}
 
-		new_slug := ""
+// SetThumbnailVariants replaces the variants of a post's thumbnail without touching updated_at.
+func (repo *PostRepo) SetThumbnailVariants(UUID string, variants string) error {
+	_, err := repo.db.ExecContext(context.Background(), `UPDATE posts SET thumbnail_variants = ?, updated_at = updated_at WHERE uuid = ?`, nullableJSON(variants), UUID)
+	return err
+}
 
-		if err != nil {
-			if err == sql.ErrNoRows {
-				return first_slug
-			}
-		}
+// RenderAll renders the content of every post again, for posts saved before rendering existed or
+// after the renderer changed.
+func (repo *PostRepo) RenderAll() (int, error) {
+	type content struct {
+		UUID    string `db:"uuid"`
+		Content string `db:"content"`
+		Format  string `db:"content_format"`
+	}
 
-		for true {
-			count++
-			new_slug = slug.Make(Title) + "-" + strconv.Itoa(count)
-			next_query := `SELECT slug FROM posts WHERE slug = ? LIMIT 1`
-			err_again := repo.db.QueryRowContext(context.Background(), next_query, new_slug).Scan(&slug_check)
-			if err_again != nil {
-				break
-			}
-		}
-		return new_slug
-	} else {
-		query = `
-			SELECT 
-			slug
-			FROM posts 
-			WHERE posts.slug = ? AND uuid != ? LIMIT 1
-		`
+	posts := []content{}
+	if err := repo.db.SelectContext(context.Background(), &posts, `SELECT uuid, content, content_format FROM posts`); err != nil {
+		return 0, err
+	}
 
-		err := repo.db.QueryRowContext(context.Background(), query, first_slug, UUID).Scan(&slug_check)
+	for i, post := range posts {
+		if err := renderPostContent(repo.db, post.UUID, post.Content, post.Format); err != nil {
+			return i, err
+		}
+	}
 
-		new_slug := ""
+	return len(posts), nil
+}
 
-		if err != nil {
-			if err == sql.ErrNoRows {
-				return first_slug
-			}
-		}
+// renderPostContent renders a post's content in its format and stores the sanitized HTML, excerpt,
+// reading time and table of contents next to it.
+func renderPostContent(db sqlx.ExecerContext, UUID string, content string, format string) error {
+	rendered, err := markup.Render(content, format)
+	if err != nil {
+		return err
+	}
 
-		for true {
-			count++
-			new_slug = slug.Make(Title) + "-" + strconv.Itoa(count)
-			next_query := `SELECT slug FROM posts WHERE slug = ? AND uuid != ? LIMIT 1`
-			err_again := repo.db.QueryRowContext(context.Background(), next_query, new_slug, UUID).Scan(&slug_check)
-			if err_again != nil {
-				break
-			}
-		}
-		return new_slug
+	toc, err := json.Marshal(rendered.TOC)
+	if err != nil {
+		return err
 	}
+
+	query := `UPDATE posts SET content_format = ?, content_html = ?, excerpt = ?, reading_time_minutes = ?, toc = ?, updated_at = updated_at WHERE uuid = ?`
+	_, err = db.ExecContext(context.Background(), query, format, rendered.HTML, rendered.Excerpt, rendered.ReadingTime, string(toc), UUID)
+	return err
 }
 
 func NewPostRepo(db *database.DB) PostRepository {
@@ -0,0 +1,367 @@
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+type PostRevisionRepository interface {
+	Index(PostUUID string) ([]model.PostRevision, error)
+	Show(PostUUID string, UUID string) (model.PostRevision, error)
+	Diff(PostUUID string, From string, To string) (model.PostRevisionDiff, error)
+	Restore(PostUUID string, UUID string, EditorUUID string) (model.Post, error)
+	Prune(PostUUID string, keep int) error
+}
+
+type PostRevisionRepo struct {
+	db *database.DB
+}
+
+// postSnapshot holds the revisioned fields of a post.
+type postSnapshot struct {
+	Title         string
+	Thumbnail     string
+	Content       string
+	ContentFormat string
+	Keyword       string
+}
+
+func (snapshot postSnapshot) fields() map[string]string {
+	return map[string]string{
+		"title":          snapshot.Title,
+		"thumbnail":      snapshot.Thumbnail,
+		"content":        snapshot.Content,
+		"content_format": snapshot.ContentFormat,
+		"keyword":        snapshot.Keyword,
+	}
+}
+
+// revisionSnapshot holds the revisioned fields of a revision.
+func revisionSnapshot(revision model.PostRevision) postSnapshot {
+	return postSnapshot{revision.Title, revision.Thumbnail, revision.Content, revision.ContentFormat, revision.Keyword}
+}
+
+// revisionFields keeps diffs and changed_fields in a stable order.
+var revisionFields = []string{"title", "thumbnail", "content", "content_format", "keyword"}
+
+func (repo *PostRevisionRepo) Index(PostUUID string) ([]model.PostRevision, error) {
+	query := `
+	SELECT
+	post_revisions.uuid,
+	post_uuid,
+	user_uuid,
+	title,
+	IFNULL(thumbnail, ''),
+	content,
+	content_format,
+	keyword,
+	changed_fields,
+	post_revisions.created_at,
+	IF(
+		users.uuid IS NULL,
+		NULL,
+		JSON_OBJECT(
+			'uuid', users.uuid,
+			'name', users.name,
+			'username', users.username,
+			'email', users.email
+		)
+	) AS user
+	FROM post_revisions LEFT JOIN users ON users.uuid = post_revisions.user_uuid
+	WHERE post_uuid = ?
+	ORDER BY post_revisions.id DESC
+	`
+
+	rows, err := repo.db.QueryContext(context.Background(), query, PostUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	items := []model.PostRevision{}
+	for rows.Next() {
+		var i model.PostRevision
+		err := rows.Scan(
+			&i.UUID,
+			&i.PostUUID,
+			&i.UserUUID,
+			&i.Title,
+			&i.Thumbnail,
+			&i.Content,
+			&i.ContentFormat,
+			&i.Keyword,
+			&i.ChangedFields,
+			&i.CreatedAt,
+			&i.User,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (repo *PostRevisionRepo) Show(PostUUID string, UUID string) (model.PostRevision, error) {
+	var revision model.PostRevision
+	query := `
+	SELECT
+	post_revisions.uuid,
+	post_uuid,
+	user_uuid,
+	title,
+	IFNULL(thumbnail, ''),
+	content,
+	content_format,
+	keyword,
+	changed_fields,
+	post_revisions.created_at,
+	IF(
+		users.uuid IS NULL,
+		NULL,
+		JSON_OBJECT(
+			'uuid', users.uuid,
+			'name', users.name,
+			'username', users.username,
+			'email', users.email
+		)
+	) AS user
+	FROM post_revisions LEFT JOIN users ON users.uuid = post_revisions.user_uuid
+	WHERE post_uuid = ? AND post_revisions.uuid = ? LIMIT 1
+	`
+
+	err := repo.db.QueryRowContext(context.Background(), query, PostUUID, UUID).Scan(
+		&revision.UUID,
+		&revision.PostUUID,
+		&revision.UserUUID,
+		&revision.Title,
+		&revision.Thumbnail,
+		&revision.Content,
+		&revision.ContentFormat,
+		&revision.Keyword,
+		&revision.ChangedFields,
+		&revision.CreatedAt,
+		&revision.User,
+	)
+
+	return revision, err
+}
+
+// Diff returns a unified diff per changed field between two revisions. An empty or "current"
+// To compares against the post as it is now.
+func (repo *PostRevisionRepo) Diff(PostUUID string, From string, To string) (model.PostRevisionDiff, error) {
+	from, err := repo.Show(PostUUID, From)
+	if err != nil {
+		return model.PostRevisionDiff{}, err
+	}
+
+	var to postSnapshot
+	if To == "" || To == "current" {
+		To = "current"
+		to, _, err = currentPostSnapshot(repo.db, PostUUID)
+	} else {
+		var revision model.PostRevision
+		revision, err = repo.Show(PostUUID, To)
+		to = revisionSnapshot(revision)
+	}
+	if err != nil {
+		return model.PostRevisionDiff{}, err
+	}
+
+	before := revisionSnapshot(from).fields()
+	after := to.fields()
+
+	diff := map[string]string{}
+	for _, field := range revisionFields {
+		if before[field] == after[field] {
+			continue
+		}
+
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(before[field] + "\n"),
+			B:        difflib.SplitLines(after[field] + "\n"),
+			FromFile: field + "@" + From,
+			ToFile:   field + "@" + To,
+			Context:  3,
+		})
+		if err != nil {
+			return model.PostRevisionDiff{}, err
+		}
+		diff[field] = text
+	}
+
+	return model.PostRevisionDiff{From: From, To: To, Diff: diff}, nil
+}
+
+// Restore copies a revision back onto the post and records the restore as a new revision.
+func (repo *PostRevisionRepo) Restore(PostUUID string, UUID string, EditorUUID string) (model.Post, error) {
+	revision, err := repo.Show(PostUUID, UUID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return model.Post{}, err
+	}
+	defer tx.Rollback()
+
+	var old_slug string
+	var slug_locked bool
+	err = tx.QueryRowContext(context.Background(), `SELECT slug, slug_locked FROM posts WHERE uuid = ? AND deleted_at IS NULL FOR UPDATE`, PostUUID).Scan(&old_slug, &slug_locked)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	// A locked slug survives the restored title.
+	slug := old_slug
+	if !slug_locked {
+		slug = postSlug(tx, revision.Title, &PostUUID, " FOR UPDATE")
+	}
+
+	// Revisions keep the thumbnail URL, its media row brings back the variants if it still exists.
+	query := `UPDATE posts SET title = ?, thumbnail = ?, thumbnail_media_uuid = (SELECT uuid FROM media WHERE url = ? LIMIT 1), thumbnail_variants = (SELECT variants FROM media WHERE url = ? LIMIT 1), content = ?, content_format = ?, keyword = ?, slug = ?, updated_at = ? WHERE uuid = ? AND deleted_at IS NULL`
+	_, err = tx.ExecContext(context.Background(), query, revision.Title, revision.Thumbnail, revision.Thumbnail, revision.Thumbnail, revision.Content, revision.ContentFormat, revision.Keyword, slug, time.Now(), PostUUID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if err := renderPostContent(tx, PostUUID, revision.Content, revision.ContentFormat); err != nil {
+		return model.Post{}, err
+	}
+
+	if err := storeSlugRedirect(tx, SlugRedirectPost, old_slug, slug, PostUUID); err != nil {
+		return model.Post{}, err
+	}
+
+	if err := storePostRevision(tx, PostUUID, EditorUUID); err != nil {
+		return model.Post{}, err
+	}
+
+	post, err := findPost(tx, PostUUID, false)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	return post, tx.Commit()
+}
+
+// Prune keeps only the newest keep revisions of a post. A keep of zero or less keeps everything.
+func (repo *PostRevisionRepo) Prune(PostUUID string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	var oldest uint64
+	query := `SELECT id FROM post_revisions WHERE post_uuid = ? ORDER BY id DESC LIMIT 1 OFFSET ?`
+	err := repo.db.QueryRowContext(context.Background(), query, PostUUID, keep-1).Scan(&oldest)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	_, err = repo.db.ExecContext(context.Background(), `DELETE FROM post_revisions WHERE post_uuid = ? AND id < ?`, PostUUID, oldest)
+	return err
+}
+
+// currentPostSnapshot reads the revisioned fields and author of a post.
+func currentPostSnapshot(db sqlx.QueryerContext, PostUUID string) (postSnapshot, string, error) {
+	var snapshot postSnapshot
+	var author string
+	query := `SELECT title, IFNULL(thumbnail, ''), content, content_format, keyword, user_uuid FROM posts WHERE uuid = ? AND deleted_at IS NULL`
+	err := db.QueryRowxContext(context.Background(), query, PostUUID).Scan(
+		&snapshot.Title,
+		&snapshot.Thumbnail,
+		&snapshot.Content,
+		&snapshot.ContentFormat,
+		&snapshot.Keyword,
+		&author,
+	)
+
+	return snapshot, author, err
+}
+
+// storePostRevision snapshots the current state of a post, recording which fields changed
+// since the previous revision. An empty editor falls back to the post author.
+func storePostRevision(tx *sqlx.Tx, PostUUID string, EditorUUID string) error {
+	current, author, err := currentPostSnapshot(tx, PostUUID)
+	if err != nil {
+		return err
+	}
+
+	if EditorUUID == "" {
+		EditorUUID = author
+	}
+
+	var previous postSnapshot
+	query := `SELECT title, IFNULL(thumbnail, ''), content, content_format, keyword FROM post_revisions WHERE post_uuid = ? ORDER BY id DESC LIMIT 1`
+	err = tx.QueryRowContext(context.Background(), query, PostUUID).Scan(
+		&previous.Title,
+		&previous.Thumbnail,
+		&previous.Content,
+		&previous.ContentFormat,
+		&previous.Keyword,
+	)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	changed := []string{}
+	before, after := previous.fields(), current.fields()
+	for _, field := range revisionFields {
+		if before[field] != after[field] {
+			changed = append(changed, field)
+		}
+	}
+
+	changed_fields, err := json.Marshal(changed)
+	if err != nil {
+		return err
+	}
+
+	insert_query := `INSERT INTO post_revisions (uuid, post_uuid, user_uuid, title, thumbnail, content, content_format, keyword, changed_fields, created_at) VALUES(?,?,?,?,?,?,?,?,?,?)`
+	_, err = tx.ExecContext(context.Background(), insert_query, uuid.New().String(), PostUUID, EditorUUID, current.Title, current.Thumbnail, current.Content, current.ContentFormat, current.Keyword, string(changed_fields), time.Now())
+
+	return err
+}
+
+// ensurePostRevision snapshots a post that predates revision history, so its original text can be restored.
+func ensurePostRevision(tx *sqlx.Tx, PostUUID string) error {
+	var count int
+	err := tx.QueryRowContext(context.Background(), `SELECT count(*) FROM post_revisions WHERE post_uuid = ?`, PostUUID).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	err = storePostRevision(tx, PostUUID, "")
+	if err == sql.ErrNoRows {
+		return nil
+	}
+
+	return err
+}
+
+func NewPostRevisionRepo(db *database.DB) PostRevisionRepository {
+	return &PostRevisionRepo{db}
+}
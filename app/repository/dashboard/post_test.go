@@ -0,0 +1,55 @@
+package dashboard
+
+import (
+	"testing"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+)
+
+func TestPostTransitionPermission(t *testing.T) {
+	tests := []struct {
+		from       string
+		to         string
+		permission string
+		err        error
+	}{
+		{model.PostStatusDraft, model.PostStatusInReview, "post-update", nil},
+		{model.PostStatusDraft, model.PostStatusScheduled, "post-publish", nil},
+		{model.PostStatusDraft, model.PostStatusPublished, "post-publish", nil},
+		{model.PostStatusDraft, model.PostStatusArchived, "", ErrInvalidTransition},
+		{model.PostStatusDraft, model.PostStatusDraft, "", ErrInvalidTransition},
+
+		{model.PostStatusInReview, model.PostStatusDraft, "post-update", nil},
+		{model.PostStatusInReview, model.PostStatusScheduled, "post-publish", nil},
+		{model.PostStatusInReview, model.PostStatusPublished, "post-publish", nil},
+		{model.PostStatusInReview, model.PostStatusArchived, "", ErrInvalidTransition},
+
+		{model.PostStatusScheduled, model.PostStatusDraft, "post-publish", nil},
+		{model.PostStatusScheduled, model.PostStatusPublished, "post-publish", nil},
+		{model.PostStatusScheduled, model.PostStatusInReview, "", ErrInvalidTransition},
+		{model.PostStatusScheduled, model.PostStatusArchived, "", ErrInvalidTransition},
+
+		{model.PostStatusPublished, model.PostStatusDraft, "post-publish", nil},
+		{model.PostStatusPublished, model.PostStatusArchived, "post-publish", nil},
+		{model.PostStatusPublished, model.PostStatusScheduled, "", ErrInvalidTransition},
+		{model.PostStatusPublished, model.PostStatusInReview, "", ErrInvalidTransition},
+
+		{model.PostStatusArchived, model.PostStatusDraft, "post-publish", nil},
+		{model.PostStatusArchived, model.PostStatusPublished, "", ErrInvalidTransition},
+
+		{"deleted", model.PostStatusDraft, "", ErrInvalidTransition},
+		{model.PostStatusDraft, "deleted", "", ErrInvalidTransition},
+	}
+
+	for _, test := range tests {
+		t.Run(test.from+" to "+test.to, func(t *testing.T) {
+			permission, err := PostTransitionPermission(test.from, test.to)
+			if err != test.err {
+				t.Fatalf("err = %v, want %v", err, test.err)
+			}
+			if permission != test.permission {
+				t.Errorf("permission = %q, want %q", permission, test.permission)
+			}
+		})
+	}
+}
@@ -12,12 +12,16 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrSystemRole is returned when a system role is about to be deleted or renamed.
+var ErrSystemRole = errors.New("system role can't be deleted or renamed")
+
 type RoleRepository interface {
 	Index(limit int, offset uint, search string, sort_by string, sort string) ([]model.Role, int, error)
 	Show(UUID string) (model.ShowRole, error)
 	Store(model *model.StoreRole) (model.Role, error)
 	Update(UUID string, request *model.UpdateRole) (model.Role, error)
 	Destroy(UUID string) (model.Role, error)
+	Permissions(UUID string) ([]string, error)
 }
 
 type RoleRepo struct {
@@ -25,7 +29,7 @@ type RoleRepo struct {
 }
 
 func (repo *RoleRepo) Index(limit int, offset uint, search string, sort_by string, sort string) ([]model.Role, int, error) {
-	_select := "uuid, name, is_active, created_at, updated_at, deleted_at"
+	_select := "uuid, name, code, is_active, is_system, created_at, updated_at, deleted_at"
 	_conditions := database.Search([]string{"name"}, search, "roles.deleted_at")
 	_order := database.OrderBy(sort_by, sort)
 	_limit := database.Limit(limit, offset)
@@ -48,7 +52,9 @@ func (repo *RoleRepo) Index(limit int, offset uint, search string, sort_by strin
 		if err := rows.Scan(
 			&i.UUID,
 			&i.Name,
+			&i.Code,
 			&i.IsActive,
+			&i.IsSystem,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
@@ -69,11 +75,13 @@ func (repo *RoleRepo) Index(limit int, offset uint, search string, sort_by strin
 
 func (repo *RoleRepo) Show(UUID string) (model.ShowRole, error) {
 	var role model.ShowRole
-	query := "SELECT uuid, name, is_active, created_at, updated_at, deleted_at FROM roles WHERE uuid = ? AND roles.deleted_at IS NULL LIMIT 1"
+	query := "SELECT uuid, name, code, is_active, is_system, created_at, updated_at, deleted_at FROM roles WHERE uuid = ? AND roles.deleted_at IS NULL LIMIT 1"
 	err := repo.db.QueryRowContext(context.Background(), query, UUID).Scan(
 		&role.UUID,
 		&role.Name,
+		&role.Code,
 		&role.IsActive,
+		&role.IsSystem,
 		&role.CreatedAt,
 		&role.UpdatedAt,
 		&role.DeletedAt,
@@ -85,13 +93,20 @@ func (repo *RoleRepo) Show(UUID string) (model.ShowRole, error) {
 }
 
 func (repo *RoleRepo) Store(request *model.StoreRole) (model.Role, error) {
-	query := `INSERT INTO roles (uuid, name, is_active, created_at) VALUES(?,?,?,?) 
-	RETURNING uuid, name, is_active, created_at`
+	var code *string
+	if request.Code != "" {
+		code = &request.Code
+	}
+
+	query := `INSERT INTO roles (uuid, name, code, is_active, created_at) VALUES(?,?,?,?,?)
+	RETURNING uuid, name, code, is_active, is_system, created_at`
 	var role model.Role
-	err := repo.db.QueryRowContext(context.Background(), query, uuid.New(), request.Name, request.IsActive, time.Now()).Scan(
+	err := repo.db.QueryRowContext(context.Background(), query, uuid.New(), request.Name, code, request.IsActive, time.Now()).Scan(
 		&role.UUID,
 		&role.Name,
+		&role.Code,
 		&role.IsActive,
+		&role.IsSystem,
 		&role.CreatedAt,
 	)
 	if err != nil {
@@ -101,6 +116,15 @@ func (repo *RoleRepo) Store(request *model.StoreRole) (model.Role, error) {
 }
 
 func (repo *RoleRepo) Update(UUID string, request *model.UpdateRole) (model.Role, error) {
+	current, err := repo.Show(UUID)
+	if err != nil {
+		return model.Role{}, err
+	}
+
+	if current.IsSystem && current.Name != request.Name {
+		return model.Role{}, ErrSystemRole
+	}
+
 	query := `UPDATE roles SET name = ?, is_active = ?, updated_at = ? WHERE uuid = ?`
 	result, err := repo.db.ExecContext(context.Background(), query, request.Name, request.IsActive, time.Now(), UUID)
 	if err != nil {
@@ -117,10 +141,12 @@ func (repo *RoleRepo) Update(UUID string, request *model.UpdateRole) (model.Role
 	}
 
 	var role model.Role
-	err = repo.db.QueryRowContext(context.Background(), "SELECT uuid, name, is_active, created_at, updated_at FROM roles WHERE uuid = ?", UUID).Scan(
+	err = repo.db.QueryRowContext(context.Background(), "SELECT uuid, name, code, is_active, is_system, created_at, updated_at FROM roles WHERE uuid = ?", UUID).Scan(
 		&role.UUID,
 		&role.Name,
+		&role.Code,
 		&role.IsActive,
+		&role.IsSystem,
 		&role.CreatedAt,
 		&role.UpdatedAt,
 	)
@@ -132,7 +158,16 @@ func (repo *RoleRepo) Update(UUID string, request *model.UpdateRole) (model.Role
 }
 
 func (repo *RoleRepo) Destroy(UUID string) (model.Role, error) {
-	query := `UPDATE roles SET updated_at = ?, deleted_at = ? WHERE uuid = ?`
+	current, err := repo.Show(UUID)
+	if err != nil {
+		return model.Role{}, err
+	}
+
+	if current.IsSystem {
+		return model.Role{}, ErrSystemRole
+	}
+
+	query := `UPDATE roles SET updated_at = ?, deleted_at = ? WHERE uuid = ? AND is_system = false`
 	result, err := repo.db.ExecContext(context.Background(), query, time.Now(), time.Now(), UUID)
 	if err != nil {
 		return model.Role{}, err
@@ -148,10 +183,12 @@ func (repo *RoleRepo) Destroy(UUID string) (model.Role, error) {
 	}
 
 	var role model.Role
-	err = repo.db.QueryRowContext(context.Background(), "SELECT uuid, name, is_active, created_at, updated_at, deleted_at FROM roles WHERE uuid = ?", UUID).Scan(
+	err = repo.db.QueryRowContext(context.Background(), "SELECT uuid, name, code, is_active, is_system, created_at, updated_at, deleted_at FROM roles WHERE uuid = ?", UUID).Scan(
 		&role.UUID,
 		&role.Name,
+		&role.Code,
 		&role.IsActive,
+		&role.IsSystem,
 		&role.CreatedAt,
 		&role.UpdatedAt,
 		&role.DeletedAt,
@@ -163,6 +200,33 @@ func (repo *RoleRepo) Destroy(UUID string) (model.Role, error) {
 	return role, nil
 }
 
+// Permissions returns the permission names granted to the role.
+func (repo *RoleRepo) Permissions(UUID string) ([]string, error) {
+	query := `SELECT permissions.name FROM role_has_permissions
+	JOIN permissions ON permissions.uuid = role_has_permissions.permission_uuid
+	WHERE role_has_permissions.role_uuid = ? AND permissions.deleted_at IS NULL`
+
+	rows, err := repo.db.QueryContext(context.Background(), query, UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	permissions := []string{}
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
 func NewRoleRepo(db *database.DB) RoleRepository {
 	return &RoleRepo{db}
 }
@@ -0,0 +1,55 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gosimple/slug"
+	"github.com/jmoiron/sqlx"
+)
+
+// Slug redirect types, one per table whose slugs are kept when they change.
+const (
+	SlugRedirectPost = "post"
+	SlugRedirectTag  = "tag"
+)
+
+var (
+	ErrInvalidSlug = errors.New("slug may only contain lowercase letters, numbers and dashes")
+	ErrSlugTaken   = errors.New("slug is already taken")
+)
+
+// checkSlug validates an explicitly chosen slug and makes sure no other row of table uses it.
+func checkSlug(db sqlx.QueryerContext, table string, Slug string, UUID string) error {
+	if !slug.IsSlug(Slug) {
+		return ErrInvalidSlug
+	}
+
+	var count int
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE slug = ? AND uuid != ?`, table)
+	if err := db.QueryRowxContext(context.Background(), query, Slug, UUID).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSlugTaken
+	}
+
+	return nil
+}
+
+// storeSlugRedirect keeps oldSlug pointing at UUID once its slug changes to newSlug. A redirect
+// matching the new slug is dropped since the slug is live again.
+func storeSlugRedirect(tx *sqlx.Tx, Type string, oldSlug string, newSlug string, UUID string) error {
+	if oldSlug == newSlug {
+		return nil
+	}
+
+	query := `INSERT INTO slug_redirects (type, old_slug, target_uuid) VALUES (?,?,?) ON DUPLICATE KEY UPDATE target_uuid = VALUES(target_uuid), created_at = CURRENT_TIMESTAMP`
+	if _, err := tx.ExecContext(context.Background(), query, Type, oldSlug, UUID); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(context.Background(), `DELETE FROM slug_redirects WHERE type = ? AND old_slug = ?`, Type, newSlug)
+	return err
+}
@@ -20,6 +20,7 @@ type TagRepository interface {
 	Update(UUID string, request *model.UpdateTag) (model.Tag, error)
 	Destroy(UUID string) (model.Tag, error)
 	GetSlug(Name string, UUID *string) string
+	CheckSlug(Slug string, UUID string) error
 }
 
 type TagRepo struct {
@@ -27,7 +28,7 @@ type TagRepo struct {
 }
 
 func (repo *TagRepo) Index(limit int, offset uint, search string, sort_by string, sort string) ([]model.Tag, int, error) {
-	_select := "uuid, name, slug, is_active, created_at, updated_at, deleted_at"
+	_select := "uuid, name, slug, slug_locked, is_active, created_at, updated_at, deleted_at"
 	_conditions := database.Search([]string{"name"}, search, "tags.deleted_at")
 	_order := database.OrderBy(sort_by, sort)
 	_limit := database.Limit(limit, offset)
@@ -51,6 +52,7 @@ func (repo *TagRepo) Index(limit int, offset uint, search string, sort_by string
 			&i.UUID,
 			&i.Name,
 			&i.Slug,
+			&i.SlugLocked,
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
@@ -72,11 +74,12 @@ func (repo *TagRepo) Index(limit int, offset uint, search string, sort_by string
 
 func (repo *TagRepo) Show(UUID string) (model.TagShow, error) {
 	var Tag model.TagShow
-	query := "SELECT uuid, name, slug, is_active, created_at, updated_at, deleted_at FROM tags WHERE uuid = ? AND tags.deleted_at IS NULL LIMIT 1"
+	query := "SELECT uuid, name, slug, slug_locked, is_active, created_at, updated_at, deleted_at FROM tags WHERE uuid = ? AND tags.deleted_at IS NULL LIMIT 1"
 	err := repo.db.QueryRowContext(context.Background(), query, UUID).Scan(
 		&Tag.UUID,
 		&Tag.Name,
 		&Tag.Slug,
+		&Tag.SlugLocked,
 		&Tag.IsActive,
 		&Tag.CreatedAt,
 		&Tag.UpdatedAt,
@@ -106,26 +109,35 @@ func (repo *TagRepo) Store(request *model.StoreTag) (model.Tag, error) {
 }
 
 func (repo *TagRepo) Update(UUID string, request *model.UpdateTag) (model.Tag, error) {
-	query := `UPDATE tags SET name = ?, slug = ?, is_active = ?, updated_at = ? WHERE uuid = ?`
-	result, err := repo.db.ExecContext(context.Background(), query, request.Name, request.Slug, request.IsActive, time.Now(), UUID)
+	tx, err := repo.db.Beginx()
 	if err != nil {
 		return model.Tag{}, err
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	var old_slug string
+	err = tx.QueryRowContext(context.Background(), `SELECT slug FROM tags WHERE uuid = ? FOR UPDATE`, UUID).Scan(&old_slug)
 	if err != nil {
 		return model.Tag{}, err
 	}
 
-	if rowsAffected == 0 {
-		return model.Tag{}, sql.ErrNoRows
+	// A nil SlugLocked leaves the lock as it is.
+	query := `UPDATE tags SET name = ?, slug = ?, slug_locked = COALESCE(?, slug_locked), is_active = ?, updated_at = ? WHERE uuid = ?`
+	_, err = tx.ExecContext(context.Background(), query, request.Name, request.Slug, request.SlugLocked, request.IsActive, time.Now(), UUID)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	if err := storeSlugRedirect(tx, SlugRedirectTag, old_slug, request.Slug, UUID); err != nil {
+		return model.Tag{}, err
 	}
 
 	var tag model.Tag
-	err = repo.db.QueryRowContext(context.Background(), "SELECT uuid, name, slug, is_active, created_at, updated_at FROM tags WHERE uuid = ?", UUID).Scan(
+	err = tx.QueryRowContext(context.Background(), "SELECT uuid, name, slug, slug_locked, is_active, created_at, updated_at FROM tags WHERE uuid = ?", UUID).Scan(
 		&tag.UUID,
 		&tag.Name,
 		&tag.Slug,
+		&tag.SlugLocked,
 		&tag.IsActive,
 		&tag.CreatedAt,
 		&tag.UpdatedAt,
@@ -134,7 +146,7 @@ func (repo *TagRepo) Update(UUID string, request *model.UpdateTag) (model.Tag, e
 		return model.Tag{}, err
 	}
 
-	return tag, nil
+	return tag, tx.Commit()
 }
 
 func (repo *TagRepo) Destroy(UUID string) (model.Tag, error) {
@@ -154,10 +166,11 @@ func (repo *TagRepo) Destroy(UUID string) (model.Tag, error) {
 	}
 
 	var tag model.Tag
-	err = repo.db.QueryRowContext(context.Background(), "SELECT uuid, name, slug, is_active, created_at, updated_at FROM tags WHERE uuid = ?", UUID).Scan(
+	err = repo.db.QueryRowContext(context.Background(), "SELECT uuid, name, slug, slug_locked, is_active, created_at, updated_at FROM tags WHERE uuid = ?", UUID).Scan(
 		&tag.UUID,
 		&tag.Name,
 		&tag.Slug,
+		&tag.SlugLocked,
 		&tag.IsActive,
 		&tag.CreatedAt,
 		&tag.UpdatedAt,
@@ -234,6 +247,11 @@ func (repo *TagRepo) GetSlug(Name string, UUID *string) string {
 	}
 }
 
+// CheckSlug validates a slug chosen by hand for the tag.
+func (repo *TagRepo) CheckSlug(Slug string, UUID string) error {
+	return checkSlug(repo.db, "tags", Slug, UUID)
+}
+
 func NewTagRepo(db *database.DB) TagRepository {
 	return &TagRepo{db}
 }
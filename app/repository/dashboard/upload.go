@@ -0,0 +1,151 @@
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrUploadOffset  = errors.New("upload offset does not match")
+	ErrUploadExpired = errors.New("upload has expired")
+)
+
+const uploadSelect = `uuid, user_uuid, filename, length, upload_offset, checksum, is_private, media_uuid, expires_at, created_at, updated_at`
+
+type UploadRepository interface {
+	Show(UUID string, UserUUID string) (model.Upload, error)
+	Store(request *model.StoreUpload) (model.Upload, error)
+	Append(UUID string, UserUUID string, offset int64, write func(upload model.Upload) (int64, error)) (model.Upload, bool, error)
+	Complete(UUID string, MediaUUID string) (model.Upload, error)
+	Release(UUID string) error
+	Destroy(UUID string, UserUUID string) (model.Upload, error)
+	Expired(before time.Time) ([]model.Upload, error)
+	DestroyExpired(before time.Time) error
+}
+
+type UploadRepo struct {
+	db *database.DB
+}
+
+// Show finds an upload of a user. Other users' uploads are not found.
+func (repo *UploadRepo) Show(UUID string, UserUUID string) (model.Upload, error) {
+	var upload model.Upload
+	err := repo.db.GetContext(context.Background(), &upload, `SELECT `+uploadSelect+` FROM uploads WHERE uuid = ? AND user_uuid = ?`, UUID, UserUUID)
+	return upload, err
+}
+
+func (repo *UploadRepo) Store(request *model.StoreUpload) (model.Upload, error) {
+	ID := uuid.New().String()
+	query := `INSERT INTO uploads (uuid, user_uuid, filename, length, checksum, is_private, expires_at, created_at) VALUES(?,?,?,?,?,?,?,?)`
+	_, err := repo.db.ExecContext(context.Background(), query, ID, request.UserUUID, request.Filename, request.Length, sql.NullString{String: request.Checksum, Valid: request.Checksum != ""}, request.IsPrivate, request.ExpiresAt, time.Now())
+	if err != nil {
+		return model.Upload{}, err
+	}
+
+	return repo.Show(ID, request.UserUUID)
+}
+
+// Append locks an upload that is at offset and lets write add the next chunk, moving the offset by
+// the bytes written. The lock keeps concurrent requests for the same upload from interleaving. It
+// also tells whether the caller claimed finishing the upload: only one request gets to, the one that
+// finds it fully received, not yet media and not being finished by another.
+func (repo *UploadRepo) Append(UUID string, UserUUID string, offset int64, write func(upload model.Upload) (int64, error)) (model.Upload, bool, error) {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return model.Upload{}, false, err
+	}
+	defer tx.Rollback()
+
+	upload, err := findUpload(tx, `SELECT `+uploadSelect+` FROM uploads WHERE uuid = ? AND user_uuid = ? FOR UPDATE`, UUID, UserUUID)
+	if err != nil {
+		return model.Upload{}, false, err
+	}
+	if upload.ExpiresAt.Before(time.Now()) {
+		return model.Upload{}, false, ErrUploadExpired
+	}
+	if upload.Offset != offset {
+		return model.Upload{}, false, ErrUploadOffset
+	}
+
+	written, err := write(upload)
+	if err != nil {
+		return model.Upload{}, false, err
+	}
+
+	if _, err := tx.ExecContext(context.Background(), `UPDATE uploads SET upload_offset = ?, updated_at = ? WHERE uuid = ?`, upload.Offset+written, time.Now(), UUID); err != nil {
+		return model.Upload{}, false, err
+	}
+	upload.Offset += written
+
+	claimed := false
+	if upload.Offset == upload.Length {
+		res, err := tx.ExecContext(context.Background(), `UPDATE uploads SET completing = TRUE WHERE uuid = ? AND media_uuid IS NULL AND completing = FALSE`, UUID)
+		if err != nil {
+			return model.Upload{}, false, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return model.Upload{}, false, err
+		}
+		claimed = affected > 0
+	}
+
+	return upload, claimed, tx.Commit()
+}
+
+// Complete records the media a finished upload became.
+func (repo *UploadRepo) Complete(UUID string, MediaUUID string) (model.Upload, error) {
+	if _, err := repo.db.ExecContext(context.Background(), `UPDATE uploads SET media_uuid = ?, updated_at = ? WHERE uuid = ?`, MediaUUID, time.Now(), UUID); err != nil {
+		return model.Upload{}, err
+	}
+
+	return findUpload(repo.db, `SELECT `+uploadSelect+` FROM uploads WHERE uuid = ?`, UUID)
+}
+
+// Release gives up finishing an upload that failed for a reason a retry may not run into.
+func (repo *UploadRepo) Release(UUID string) error {
+	_, err := repo.db.ExecContext(context.Background(), `UPDATE uploads SET completing = FALSE WHERE uuid = ?`, UUID)
+	return err
+}
+
+func (repo *UploadRepo) Destroy(UUID string, UserUUID string) (model.Upload, error) {
+	upload, err := repo.Show(UUID, UserUUID)
+	if err != nil {
+		return model.Upload{}, err
+	}
+
+	if _, err := repo.db.ExecContext(context.Background(), `DELETE FROM uploads WHERE uuid = ?`, UUID); err != nil {
+		return model.Upload{}, err
+	}
+
+	return upload, nil
+}
+
+// Expired lists uploads that expired before a time, finished or not.
+func (repo *UploadRepo) Expired(before time.Time) ([]model.Upload, error) {
+	uploads := []model.Upload{}
+	err := repo.db.SelectContext(context.Background(), &uploads, `SELECT `+uploadSelect+` FROM uploads WHERE expires_at < ?`, before)
+	return uploads, err
+}
+
+func (repo *UploadRepo) DestroyExpired(before time.Time) error {
+	_, err := repo.db.ExecContext(context.Background(), `DELETE FROM uploads WHERE expires_at < ?`, before)
+	return err
+}
+
+func findUpload(db sqlx.QueryerContext, query string, args ...interface{}) (model.Upload, error) {
+	var upload model.Upload
+	err := sqlx.GetContext(context.Background(), db, &upload, query, args...)
+	return upload, err
+}
+
+func NewUploadRepo(db *database.DB) UploadRepository {
+	return &UploadRepo{db}
+}
@@ -0,0 +1,34 @@
+// Package repository holds the SQL the dashboard and public repositories share.
+package repository
+
+import "fmt"
+
+// PostTags selects the tags of the current posts row as a JSON array.
+const PostTags = `IFNULL(
+        (
+            SELECT JSON_ARRAYAGG(
+                JSON_OBJECT(
+                    'uuid', tags.uuid,
+                    'name', tags.name,
+                    'slug', tags.slug,
+                    'is_active', tags.is_active,
+                    'created_at', tags.created_at,
+                    'updated_at', tags.updated_at
+                )
+            )
+            FROM post_tags JOIN tags ON tags.uuid = post_tags.tag_uuid
+            WHERE post_tags.post_uuid = posts.uuid AND tags.deleted_at IS NULL
+        ),
+        JSON_ARRAY()
+    )`
+
+// PostTagNames is a searchable column holding the comma separated tag names of the current posts row.
+const PostTagNames = `(SELECT GROUP_CONCAT(tags.name) FROM post_tags JOIN tags ON tags.uuid = post_tags.tag_uuid WHERE post_tags.post_uuid = posts.uuid AND tags.deleted_at IS NULL)`
+
+// PostFullText is the condition of a FULLTEXT post search with match, its MATCH expression, and its
+// arguments. Author and tag names aren't in the index, so they are matched with LIKE as the fallback
+// for short terms does, finding the same posts whatever the length of the terms.
+func PostFullText(match string, search string) (string, []interface{}) {
+	like := "%" + search + "%"
+	return fmt.Sprintf("(%s OR users.name LIKE ? OR %s LIKE ?)", match, PostTagNames), []interface{}{search, like, like}
+}
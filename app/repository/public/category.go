@@ -0,0 +1,130 @@
+package public
+
+import (
+	"context"
+	"fmt"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/public"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+)
+
+// categoryRecursionLimit stops the recursive subtree query on rows that somehow form a loop.
+const categoryRecursionLimit = 64
+
+// categorySubtree selects the active category with the given uuid and, down to the given depth, its
+// active descendants. A depth of 1 is the category alone.
+const categorySubtree = `WITH RECURSIVE subtree (uuid, depth) AS (
+		SELECT uuid, 1 FROM categories WHERE uuid = ?
+		UNION ALL
+		SELECT categories.uuid, subtree.depth + 1
+		FROM categories JOIN subtree ON categories.parent_uuid = subtree.uuid
+		WHERE categories.is_active = true AND categories.deleted_at IS NULL AND subtree.depth < ?
+	)`
+
+type CategoryRepository interface {
+	Tree() ([]*model.CategoryNode, error)
+	CategoryPost(slug string, include_descendants bool, limit int, offset uint, sort_by string, sort string) (model.CategoryWithPost, int, error)
+}
+
+type CategoryRepo struct {
+	db *database.DB
+}
+
+// Tree returns the active categories nested under their parents, each with the number of published
+// posts filed directly under it. Children of an inactive category are left out with it.
+func (repo *CategoryRepo) Tree() ([]*model.CategoryNode, error) {
+	query := fmt.Sprintf(`
+	SELECT
+	categories.uuid,
+	categories.parent_uuid,
+	categories.name,
+	categories.slug,
+	(SELECT COUNT(*) FROM posts WHERE posts.category_uuid = categories.uuid AND %s AND posts.deleted_at IS NULL) AS post_count
+	FROM categories
+	WHERE categories.is_active = true AND categories.deleted_at IS NULL
+	ORDER BY categories.name ASC
+	`, publishedPost)
+
+	categories := []*model.CategoryNode{}
+	if err := repo.db.SelectContext(context.Background(), &categories, query); err != nil {
+		return nil, err
+	}
+
+	nodes := map[string]*model.CategoryNode{}
+	for _, category := range categories {
+		category.Children = []*model.CategoryNode{}
+		nodes[category.UUID] = category
+	}
+
+	roots := []*model.CategoryNode{}
+	for _, category := range categories {
+		if category.ParentUUID == nil {
+			roots = append(roots, category)
+		} else if parent, ok := nodes[*category.ParentUUID]; ok {
+			parent.Children = append(parent.Children, category)
+		}
+	}
+
+	return roots, nil
+}
+
+// CategoryPost lists the published posts of an active category, including those of its
+// descendants when asked to.
+func (repo *CategoryRepo) CategoryPost(slug string, include_descendants bool, limit int, offset uint, sort_by string, sort string) (model.CategoryWithPost, int, error) {
+	category := model.CategoryWithPost{IncludeDescendants: include_descendants}
+	err := repo.db.QueryRowContext(context.Background(), `SELECT uuid, name, slug FROM categories WHERE slug = ? AND is_active = true AND deleted_at IS NULL LIMIT 1`, slug).Scan(
+		&category.UUID,
+		&category.Name,
+		&category.Slug,
+	)
+	if err != nil {
+		return model.CategoryWithPost{}, 0, err
+	}
+
+	depth := 1
+	if include_descendants {
+		depth = categoryRecursionLimit
+	}
+
+	_conditions := fmt.Sprintf(`WHERE posts.category_uuid IN (SELECT uuid FROM subtree) AND %s AND posts.deleted_at IS NULL`, publishedPost)
+	_order := database.OrderBy(sort_by, sort)
+	_limit := database.Limit(limit, offset)
+
+	count_query := fmt.Sprintf(`%s SELECT count(*) FROM posts %s`, categorySubtree, _conditions)
+	var count int
+	_ = repo.db.QueryRow(count_query, category.UUID, depth).Scan(&count)
+
+	query := fmt.Sprintf(`%s
+	SELECT %s
+	FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid
+	%s %s %s
+	`, categorySubtree, postSelect, _conditions, _order, _limit)
+
+	rows, err := repo.db.QueryContext(context.Background(), query, category.UUID, depth)
+	if err != nil {
+		return model.CategoryWithPost{}, 0, err
+	}
+
+	defer rows.Close()
+	category.Post = []model.Post{}
+	for rows.Next() {
+		var i model.Post
+		err := scanPost(rows, &i)
+		if err != nil {
+			return model.CategoryWithPost{}, 0, err
+		}
+		category.Post = append(category.Post, i)
+	}
+	if err := rows.Close(); err != nil {
+		return model.CategoryWithPost{}, 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return model.CategoryWithPost{}, 0, err
+	}
+
+	return category, count, nil
+}
+
+func NewCategoryRepo(db *database.DB) CategoryRepository {
+	return &CategoryRepo{db}
+}
@@ -0,0 +1,254 @@
+package public
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/public"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/google/uuid"
+)
+
+// ErrCommentParentNotFound is returned when a reply targets a comment that isn't visible on the post.
+var ErrCommentParentNotFound = errors.New("parent comment not found")
+
+// approvedCommentCount counts the approved comments of the current posts row.
+const approvedCommentCount = `(SELECT count(*) FROM comments WHERE comments.post_uuid = posts.uuid AND comments.status = 'approved' AND comments.deleted_at IS NULL)`
+
+// commentSelect selects a comment with its author, where c is the comments alias.
+const commentSelect = `
+	c.uuid,
+	c.post_uuid,
+	c.parent_uuid,
+	COALESCE(users.name, c.guest_name, '') AS name,
+	c.content,
+	c.status,
+	c.created_at,
+	c.updated_at,
+	IF(
+		users.uuid IS NULL,
+		NULL,
+		JSON_OBJECT(
+			'uuid', users.uuid,
+			'name', users.name,
+			'username', users.username
+		)
+	) AS user`
+
+type CommentRepository interface {
+	Index(slug string, limit int, offset uint, sort string) ([]model.Comment, int, error)
+	Store(slug string, request *model.StoreComment) (model.Comment, error)
+}
+
+type CommentRepo struct {
+	db *database.DB
+}
+
+// Index pages through the approved top-level comments of a published post. Every comment carries its
+// approved replies, in posting order, with parent_uuid so clients can rebuild the thread.
+func (repo *CommentRepo) Index(slug string, limit int, offset uint, sort string) ([]model.Comment, int, error) {
+	post_uuid, err := repo.publishedPostUUID(slug)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	_order := database.OrderBy("c.id", sort)
+	_limit := database.Limit(limit, offset)
+
+	count_query := `SELECT count(*) FROM comments WHERE post_uuid = ? AND root_uuid IS NULL AND status = 'approved' AND deleted_at IS NULL`
+	var count int
+	_ = repo.db.QueryRow(count_query, post_uuid).Scan(&count)
+
+	query := fmt.Sprintf(`
+	SELECT %s
+	FROM comments AS c LEFT JOIN users ON users.uuid = c.user_uuid
+	WHERE c.post_uuid = ? AND c.root_uuid IS NULL AND c.status = 'approved' AND c.deleted_at IS NULL
+	%s %s
+	`, commentSelect, _order, _limit)
+
+	rows, err := repo.db.QueryContext(context.Background(), query, post_uuid)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	items := []model.Comment{}
+	for rows.Next() {
+		var i model.Comment
+		err := rows.Scan(
+			&i.UUID,
+			&i.PostUUID,
+			&i.ParentUUID,
+			&i.Name,
+			&i.Content,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.User,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		i.Replies = []model.Reply{}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := repo.replies(items); err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+// Store adds a comment or reply to a published post. Replies can only target approved comments of the same post.
+func (repo *CommentRepo) Store(slug string, request *model.StoreComment) (model.Comment, error) {
+	post_uuid, err := repo.publishedPostUUID(slug)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	var root_uuid, parent_uuid *string
+	if request.ParentUUID != "" {
+		var parent string
+		var parent_root *string
+		query := `SELECT uuid, root_uuid FROM comments WHERE uuid = ? AND post_uuid = ? AND status = 'approved' AND deleted_at IS NULL LIMIT 1`
+		err := repo.db.QueryRowContext(context.Background(), query, request.ParentUUID, post_uuid).Scan(&parent, &parent_root)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return model.Comment{}, ErrCommentParentNotFound
+			}
+			return model.Comment{}, err
+		}
+
+		parent_uuid = &parent
+		root_uuid = parent_root
+		if root_uuid == nil {
+			root_uuid = &parent
+		}
+	}
+
+	var user_uuid, guest_name, guest_email *string
+	if request.UserUUID != "" {
+		user_uuid = &request.UserUUID
+	} else {
+		guest_name = &request.Name
+		guest_email = &request.Email
+	}
+
+	ID := uuid.New().String()
+
+	query := `INSERT INTO comments (uuid, post_uuid, root_uuid, parent_uuid, user_uuid, guest_name, guest_email, content, status, created_at) VALUES(?,?,?,?,?,?,?,?,?,?)`
+	_, err = repo.db.ExecContext(context.Background(), query, ID, post_uuid, root_uuid, parent_uuid, user_uuid, guest_name, guest_email, request.Content, request.Status, time.Now())
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	var comment model.Comment
+	show_query := fmt.Sprintf(`SELECT %s FROM comments AS c LEFT JOIN users ON users.uuid = c.user_uuid WHERE c.uuid = ? LIMIT 1`, commentSelect)
+	err = repo.db.QueryRowContext(context.Background(), show_query, ID).Scan(
+		&comment.UUID,
+		&comment.PostUUID,
+		&comment.ParentUUID,
+		&comment.Name,
+		&comment.Content,
+		&comment.Status,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.User,
+	)
+
+	return comment, err
+}
+
+// replies fills in the approved replies of the comments, in posting order.
+func (repo *CommentRepo) replies(comments []model.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	index := map[string]int{}
+	placeholders := []string{}
+	args := []interface{}{}
+	for i, comment := range comments {
+		index[comment.UUID.String()] = i
+		placeholders = append(placeholders, "?")
+		args = append(args, comment.UUID.String())
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+	c.root_uuid,
+	c.uuid,
+	c.parent_uuid,
+	COALESCE(users.name, c.guest_name, '') AS name,
+	c.content,
+	c.created_at,
+	c.updated_at,
+	IF(
+		users.uuid IS NULL,
+		NULL,
+		JSON_OBJECT(
+			'uuid', users.uuid,
+			'name', users.name,
+			'username', users.username
+		)
+	) AS user
+	FROM comments AS c LEFT JOIN users ON users.uuid = c.user_uuid
+	WHERE c.root_uuid IN (%s) AND c.status = 'approved' AND c.deleted_at IS NULL
+	ORDER BY c.id
+	`, strings.Join(placeholders, ","))
+
+	rows, err := repo.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var root_uuid string
+		var i model.Reply
+		err := rows.Scan(
+			&root_uuid,
+			&i.UUID,
+			&i.ParentUUID,
+			&i.Name,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.User,
+		)
+		if err != nil {
+			return err
+		}
+		if n, ok := index[root_uuid]; ok {
+			comments[n].Replies = append(comments[n].Replies, i)
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	return rows.Err()
+}
+
+func (repo *CommentRepo) publishedPostUUID(slug string) (string, error) {
+	var post_uuid string
+	query := fmt.Sprintf(`SELECT uuid FROM posts WHERE slug = ? AND %s AND deleted_at IS NULL LIMIT 1`, publishedPost)
+	err := repo.db.QueryRowContext(context.Background(), query, slug).Scan(&post_uuid)
+
+	return post_uuid, err
+}
+
+func NewCommentRepo(db *database.DB) CommentRepository {
+	return &CommentRepo{db}
+}
@@ -3,70 +3,110 @@ package public
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/public"
+	"github.com/arif-x/sqlx-mysql-boilerplate/app/repository"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/highlight"
 )
 
+// publishedPost limits a query to posts that are published and whose publication date has passed.
+const publishedPost = `posts.status = 'published' AND posts.published_at <= NOW()`
+
+// postSelect selects a published post with its comment count, author and tags, the columns scanPost reads.
+const postSelect = `
+	posts.uuid,
+	posts.user_uuid,
+	posts.title,
+	posts.thumbnail,
+	posts.thumbnail_variants,
+	posts.content,
+	posts.content_format,
+	posts.content_html,
+	posts.excerpt,
+	posts.reading_time_minutes,
+	posts.toc,
+	posts.keyword,
+	posts.slug,
+	posts.published_at,
+	posts.is_active,
+	posts.is_highlight,
+	` + approvedCommentCount + ` AS comment_count,
+	posts.created_at,
+	posts.updated_at,
+	IF(
+		users.uuid IS NULL,
+		NULL,
+		JSON_OBJECT(
+			'uuid', users.uuid,
+			'name', users.name,
+			'username', users.username,
+			'email', users.email,
+			'created_at', users.created_at,
+			'updated_at', users.updated_at
+		)
+	) AS user,
+	` + repository.PostTags + ` AS tags`
+
 type PostRepository interface {
-	Index(limit int, offset uint, search string, sort_by string, sort string) ([]model.Post, int, error)
+	Index(limit int, offset uint, search string, search_mode string, sort_by string, sort string) ([]model.Post, int, error)
 	TagPost(slug string, limit int, offset uint, search string, sort_by string, sort string) (model.TagWithPost, int, error)
 	UserPost(username string, limit int, offset uint, search string, sort_by string, sort string) (model.UserWithPost, int, error)
 	Show(slug string) (model.PostSingle, error)
+	Feed(tag_slug string, username string, limit int) (model.Feed, error)
+	Sitemap() ([]model.SitemapEntry, error)
+	Related(slug string, limit int) ([]model.RelatedPost, error)
 }
 
 type PostRepo struct {
 	db *database.DB
 }
 
-func (repo *PostRepo) Index(limit int, offset uint, search string, sort_by string, sort string) ([]model.Post, int, error) {
-	_select := `
-	posts.uuid,
-    user_uuid,
-    tag_uuid,
-    title,
-    thumbnail,
-    content,
-    keyword,
-    posts.slug,
-    posts.is_active,
-    is_highlight,
-    posts.created_at,
-    posts.updated_at,
-    IFNULL(
-        JSON_OBJECT(
-            'uuid', users.uuid,
-            'name', users.name,
-            'username', users.username,
-            'email', users.email,
-            'created_at', users.created_at,
-            'updated_at', users.updated_at
-        ),
-        NULL
-    ) AS user,
-    IFNULL(
-        JSON_OBJECT(
-            'uuid', tags.uuid,
-            'name', tags.name,
-            'slug', tags.slug,
-            'is_active', tags.is_active,
-            'created_at', tags.created_at,
-            'updated_at', tags.updated_at
-        ),
-        NULL
-    ) AS tag
-	`
-	_conditions := database.Search([]string{"title", "content", "users.name", "tags.name"}, search, "posts.deleted_at")
-	_order := database.OrderBy(sort_by, sort)
+func (repo *PostRepo) Index(limit int, offset uint, search string, search_mode string, sort_by string, sort string) ([]model.Post, int, error) {
+	match, fulltext := repo.db.FullText([]string{"title", "content", "keyword"}, search, search_mode)
+	relevance := "NULL"
+	if fulltext {
+		relevance = match
+	}
+
+	_select := fmt.Sprintf(`%s,
+	%s AS relevance
+	`, postSelect, relevance)
+
+	// Terms shorter than the FULLTEXT minimum token size fall back to LIKE.
+	_conditions := ""
+	args := []interface{}{}
+	if fulltext {
+		condition, condition_args := repository.PostFullText(match, search)
+		_conditions = fmt.Sprintf(" WHERE %s AND posts.deleted_at IS NULL", condition)
+		args = append(args, condition_args...)
+	} else {
+		_conditions = database.Search([]string{"title", "content", "users.name", repository.PostTagNames}, search, "posts.deleted_at")
+	}
+
+	_order := ""
+	if sort_by == "relevance" && fulltext {
+		_order = database.OrderBy("relevance", sort)
+	} else if sort_by == "relevance" {
+		_order = database.OrderBy("posts.id", sort)
+	} else {
+		_order = database.OrderBy(sort_by, sort)
+	}
+
 	_limit := database.Limit(limit, offset)
 
-	count_query := fmt.Sprintf(`SELECT count(*) FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid LEFT JOIN tags ON tags.uuid = posts.tag_uuid %s AND posts.deleted_at IS NULL`, _conditions)
+	count_query := fmt.Sprintf(`SELECT count(*) FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid %s AND %s`, _conditions, publishedPost)
 	var count int
-	_ = repo.db.QueryRow(count_query).Scan(&count)
+	_ = repo.db.QueryRow(count_query, args...).Scan(&count)
 
-	query := fmt.Sprintf(`SELECT %s FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid LEFT JOIN tags ON tags.uuid = posts.tag_uuid %s %s %s`, _select, _conditions, _order, _limit)
+	query := fmt.Sprintf(`SELECT %s FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid %s AND %s %s %s`, _select, _conditions, publishedPost, _order, _limit)
+	// The relevance in the select list comes before the conditions.
+	if fulltext {
+		args = append([]interface{}{search}, args...)
+	}
 
-	rows, err := repo.db.QueryContext(context.Background(), query)
+	rows, err := repo.db.QueryContext(context.Background(), query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -75,25 +115,14 @@ func (repo *PostRepo) Index(limit int, offset uint, search string, sort_by strin
 	items := []model.Post{}
 	for rows.Next() {
 		var i model.Post
-		err := rows.Scan(
-			&i.UUID,
-			&i.TagUUID,
-			&i.UserUUID,
-			&i.Title,
-			&i.Thumbnail,
-			&i.Content,
-			&i.Keyword,
-			&i.Slug,
-			&i.IsActive,
-			&i.IsHighlight,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.User,
-			&i.Tag,
-		)
+		err := scanPost(rows, &i, &i.Relevance)
 		if err != nil {
 			return nil, 0, err
 		}
+		if search != "" {
+			snippet := highlight.Snippet(i.Content, database.SearchTerms(search), 80)
+			i.Snippet = &snippet
+		}
 		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
@@ -111,300 +140,107 @@ func (repo *PostRepo) Index(limit int, offset uint, search string, sort_by strin
 }
 
 func (repo *PostRepo) TagPost(slug string, limit int, offset uint, search string, sort_by string, sort string) (model.TagWithPost, int, error) {
-	_limit := database.Limit(limit, offset)
-	_conditions := database.SearchOther([]string{"title", "content", "users.name"}, search, "posts.deleted_at")
-	_order := database.OrderBy(sort_by, sort)
-	_select := fmt.Sprintf(`
-	tags.uuid,
-    tags.name,
-    tags.slug,
-    IFNULL(
-        CONCAT('[', 
-            IF(
-                COUNT(posts.uuid) > 0,
-                GROUP_CONCAT(
-                    DISTINCT JSON_OBJECT(
-                        'uuid', posts.uuid,
-                        'tag_uuid', posts.tag_uuid,
-                        'user_uuid', posts.user_uuid,
-                        'title', posts.title,
-                        'thumbnail', posts.thumbnail,
-                        'content', posts.content,
-                        'keyword', posts.keyword,
-                        'slug', posts.slug,
-                        'is_active', posts.is_active,
-                        'is_highlight', posts.is_highlight,
-                        'user', IF(
-                            user_uuid_u IS NULL,
-                            NULL,
-                            JSON_OBJECT(
-                                'uuid', user_uuid_u,
-                                'name', user_name,
-                                'username', user_username,
-                                'email', user_email,
-                                'created_at', user_created_at,
-                                'updated_at', user_updated_at
-                            )
-                        ),
-                        'tag', IF(
-                            tag_uuid_u IS NULL,
-                            NULL,
-                            JSON_OBJECT(
-                                'uuid', tag_uuid_u,
-                                'name', tag_name,
-                                'slug', tag_slug,
-                                'created_at', tag_created_at,
-                                'updated_at', tag_updated_at
-                            )
-                        )
-                    )
-                ),
-                ''
-            )
-        , ']'),
-        '[]'
-    ) AS post,
-    tags.created_at,
-    tags.updated_at
-	`)
-
-	count_query := fmt.Sprintf(`SELECT count(*) FROM posts LEFT JOIN tags ON tags.uuid = posts.tag_uuid LEFT JOIN users ON users.uuid = posts.user_uuid WHERE tags.slug = ? AND posts.is_active = true AND posts.deleted_at IS NULL %s`, _conditions)
-	var count int
-	_ = repo.db.QueryRow(count_query, slug).Scan(&count)
-
-	query := fmt.Sprintf(`SELECT %s FROM tags 
-	LEFT JOIN (
-		SELECT 
-			posts.*, 
-            users.uuid as user_uuid_u, users.name as user_name, users.username as user_username, users.email as user_email, users.created_at as user_created_at, users.updated_at as user_updated_at, 
-        	tags.uuid as tag_uuid_u, tags.name as tag_name, tags.slug as tag_slug, tags.created_at as tag_created_at, tags.updated_at as tag_updated_at 
-    	FROM posts
-	    LEFT JOIN users ON users.uuid = posts.user_uuid
-	    LEFT JOIN tags ON tags.uuid = posts.tag_uuid
-	    WHERE tags.slug = ? AND posts.is_active = true %s
-	    %s %s
-	) as posts ON posts.tag_uuid = tags.uuid
-	WHERE tags.slug = ? AND tags.is_active = true
-	GROUP BY tags.uuid, tags.name, tags.slug, tags.created_at, tags.updated_at
-	`, _select, _conditions, _order, _limit)
-
 	var items model.TagWithPost
-
-	err := repo.db.QueryRowContext(context.Background(), query, slug, slug).Scan(
+	err := repo.db.QueryRowContext(context.Background(), `SELECT uuid, name, slug, created_at, updated_at FROM tags WHERE slug = ? AND is_active = true AND deleted_at IS NULL LIMIT 1`, slug).Scan(
 		&items.UUID,
 		&items.Name,
 		&items.Slug,
-		&items.Post,
 		&items.CreatedAt,
 		&items.UpdatedAt,
 	)
+	if err != nil {
+		return model.TagWithPost{}, 0, err
+	}
 
+	where := `EXISTS (SELECT 1 FROM post_tags WHERE post_tags.post_uuid = posts.uuid AND post_tags.tag_uuid = ?)`
+	posts, count, err := repo.page(where, items.UUID, limit, offset, search, sort_by, sort)
 	if err != nil {
 		return model.TagWithPost{}, 0, err
 	}
+	items.Post = posts
 
 	return items, count, nil
 }
 
 func (repo *PostRepo) UserPost(username string, limit int, offset uint, search string, sort_by string, sort string) (model.UserWithPost, int, error) {
-	_limit := database.Limit(limit, offset)
-	_conditions := database.SearchOther([]string{"title", "content", "users.name"}, search, "posts.deleted_at")
-	_order := database.OrderBy(sort_by, sort)
-	_select := fmt.Sprintf(`
-	users.uuid,
-    users.name,
-    users.username,
-    users.email,
-    IFNULL(
-        CONCAT('[', 
-            IF(
-                COUNT(posts.uuid) > 0,
-                GROUP_CONCAT(
-                    DISTINCT JSON_OBJECT(
-                        'uuid', posts.uuid,
-                        'tag_uuid', posts.tag_uuid,
-                        'user_uuid', posts.user_uuid,
-                        'title', posts.title,
-                        'thumbnail', posts.thumbnail,
-                        'content', posts.content,
-                        'keyword', posts.keyword,
-                        'slug', posts.slug,
-                        'is_active', posts.is_active,
-                        'is_highlight', posts.is_highlight,
-                        'user', IF(
-                            user_uuid_u IS NULL,
-                            NULL,
-                            JSON_OBJECT(
-                                'uuid', user_uuid_u,
-                                'name', user_name,
-                                'username', user_username,
-                                'email', user_email,
-                                'created_at', user_created_at,
-                                'updated_at', user_updated_at
-                            )
-                        ),
-                        'tag', IF(
-                            tag_uuid_u IS NULL,
-                            NULL,
-                            JSON_OBJECT(
-                                'uuid', tag_uuid_u,
-                                'name', tag_name,
-                                'slug', tag_slug,
-                                'created_at', tag_created_at,
-                                'updated_at', tag_updated_at
-                            )
-                        )
-                    )
-                ),
-                ''
-            )
-        , ']'),
-        '[]'
-    ) AS post,
-    users.created_at,
-    users.updated_at
-	`)
-
-	count_query := fmt.Sprintf(`SELECT count(*) FROM posts JOIN users ON users.uuid = posts.user_uuid WHERE username = ? AND posts.is_active = true AND posts.deleted_at IS NULL %s`, _conditions)
-	var count int
-	_ = repo.db.QueryRow(count_query, username).Scan(&count)
-
-	query := fmt.Sprintf(`SELECT %s FROM users
-	LEFT JOIN (
-		SELECT 
-			posts.*, 
-            users.uuid as user_uuid_u, users.name as user_name, users.username as user_username, users.email as user_email, users.created_at as user_created_at, users.updated_at as user_updated_at, 
-        	tags.uuid as tag_uuid_u, tags.name as tag_name, tags.slug as tag_slug, tags.created_at as tag_created_at, tags.updated_at as tag_updated_at 
-    	FROM posts
-	    LEFT JOIN users ON users.uuid = posts.user_uuid
-	    LEFT JOIN tags ON tags.uuid = posts.tag_uuid
-	    WHERE users.username = ? AND posts.is_active = true %s
-	    %s %s
-	) as posts ON posts.user_uuid = users.uuid
-	WHERE username = ?
-	GROUP BY users.uuid, users.name, users.username, users.email, users.created_at, users.updated_at
-	`, _select, _conditions, _order, _limit)
-
 	var items model.UserWithPost
-
-	err := repo.db.QueryRowContext(context.Background(), query, username, username).Scan(
+	err := repo.db.QueryRowContext(context.Background(), `SELECT uuid, name, username, email, created_at, updated_at FROM users WHERE username = ? AND deleted_at IS NULL LIMIT 1`, username).Scan(
 		&items.UUID,
 		&items.Name,
 		&items.Username,
 		&items.Email,
-		&items.Post,
 		&items.CreatedAt,
 		&items.UpdatedAt,
 	)
+	if err != nil {
+		return model.UserWithPost{}, 0, err
+	}
 
+	posts, count, err := repo.page(`posts.user_uuid = ?`, items.UUID, limit, offset, search, sort_by, sort)
 	if err != nil {
 		return model.UserWithPost{}, 0, err
 	}
+	items.Post = posts
+
+	return items, count, nil
+}
+
+// page pages through the published posts matching where, a condition with a single placeholder for arg.
+func (repo *PostRepo) page(where string, arg interface{}, limit int, offset uint, search string, sort_by string, sort string) ([]model.Post, int, error) {
+	_conditions := database.SearchOther([]string{"title", "content", "users.name"}, search, "posts.deleted_at")
+	_order := database.OrderBy(sort_by, sort)
+	_limit := database.Limit(limit, offset)
+
+	count_query := fmt.Sprintf(`SELECT count(*) FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid WHERE %s AND %s %s`, where, publishedPost, _conditions)
+	var count int
+	_ = repo.db.QueryRow(count_query, arg).Scan(&count)
+
+	query := fmt.Sprintf(`SELECT %s FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid WHERE %s AND %s %s %s %s`, postSelect, where, publishedPost, _conditions, _order, _limit)
+
+	rows, err := repo.db.QueryContext(context.Background(), query, arg)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	items := []model.Post{}
+	for rows.Next() {
+		var i model.Post
+		err := scanPost(rows, &i)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
 
 	return items, count, nil
 }
 
 func (repo *PostRepo) Show(slug string) (model.PostSingle, error) {
 	var post model.PostSingle
-	query := `
-	SELECT 
-	posts.uuid,
-    user_uuid,
-    tag_uuid,
-    title,
-    thumbnail,
-    content,
-    keyword,
-    posts.slug,
-    posts.is_active,
-    is_highlight,
-    posts.created_at,
-    posts.updated_at,
-    IFNULL(
-        JSON_OBJECT(
-            'uuid', users.uuid,
-            'name', users.name,
-            'username', users.username,
-            'email', users.email,
-            'created_at', users.created_at,
-            'updated_at', users.updated_at
-        ),
-        NULL
-    ) AS user,
-    IFNULL(
-        JSON_OBJECT(
-            'uuid', tags.uuid,
-            'name', tags.name,
-            'slug', tags.slug,
-            'is_active', tags.is_active,
-            'created_at', tags.created_at,
-            'updated_at', tags.updated_at
-        ),
-        NULL
-    ) AS tag
-	FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid LEFT JOIN tags ON tags.uuid = posts.tag_uuid
-	WHERE posts.slug = $1 AND posts.is_active = true AND posts.deleted_at IS NULL LIMIT 1
-	`
-
-	err := repo.db.QueryRowContext(context.Background(), query, slug).Scan(
-		&post.UUID,
-		&post.TagUUID,
-		&post.UserUUID,
-		&post.Title,
-		&post.Thumbnail,
-		&post.Content,
-		&post.Keyword,
-		&post.Slug,
-		&post.IsActive,
-		&post.IsHighlight,
-		&post.CreatedAt,
-		&post.UpdatedAt,
-		&post.User,
-		&post.Tag,
-	)
+	query := fmt.Sprintf(`
+	SELECT %s
+	FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid
+	WHERE posts.slug = ? AND %s AND posts.deleted_at IS NULL LIMIT 1
+	`, postSelect, publishedPost)
+
+	err := scanPost(repo.db.QueryRowContext(context.Background(), query, slug), &post.Post)
 
 	if err != nil {
 		return model.PostSingle{}, err
 	}
 
-	similiar_query := `
-	SELECT 
-	posts.uuid,
-	user_uuid,
-	tag_uuid,
-    title,
-    thumbnail,
-    content,
-	keyword,
-	posts.slug,
-	posts.is_active,
-	is_highlight,
-    posts.created_at,
-    posts.updated_at,
-	CASE 
-    	WHEN users.uuid IS NULL THEN null
-    	ELSE jsonb_build_object(
-        	'uuid', users.uuid,
-        	'name', users.name,
-        	'username', users.username,
-        	'email', users.email,
-        	'created_at', users.created_at,
-        	'updated_at', users.updated_at
-    	)
-	END AS user,
-	CASE 
-    	WHEN tags.uuid IS NULL THEN null
-		ELSE jsonb_build_object(
-        	'uuid', tags.uuid,
-        	'name', tags.name,
-			'slug', tags.slug,
-        	'created_at', tags.created_at,
-        	'updated_at', tags.updated_at
-		)
-	END AS tag
-	FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid LEFT JOIN tags ON tags.uuid = posts.tag_uuid
-	WHERE posts.title LIKE $1 AND posts.slug != $2 AND posts.is_active = true AND posts.deleted_at IS NULL LIMIT 5
-	`
+	similiar_query := fmt.Sprintf(`
+	SELECT %s
+	FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid
+	WHERE posts.title LIKE ? AND posts.slug != ? AND %s AND posts.deleted_at IS NULL LIMIT 5
+	`, postSelect, publishedPost)
 
 	rows, err := repo.db.QueryContext(context.Background(), similiar_query, "%"+post.Title+"%", post.Slug)
 	if err != nil {
@@ -415,37 +251,268 @@ func (repo *PostRepo) Show(slug string) (model.PostSingle, error) {
 	var items []*model.Post
 	for rows.Next() {
 		var i model.Post
+		err := scanPost(rows, &i)
+		if err != nil {
+			return post, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return post, err
+	}
+	if err := rows.Err(); err != nil {
+		return post, err
+	}
+
+	post.Similiar = items
+
+	return post, err
+}
+
+// Feed returns the latest published posts, optionally limited to a tag or an author. Name is the
+// tag or author name, and sql.ErrNoRows is returned when either doesn't exist.
+func (repo *PostRepo) Feed(tag_slug string, username string, limit int) (model.Feed, error) {
+	var feed model.Feed
+
+	_conditions := ""
+	args := []interface{}{}
+	if tag_slug != "" {
+		err := repo.db.QueryRowContext(context.Background(), `SELECT name FROM tags WHERE slug = ? AND is_active = true AND deleted_at IS NULL LIMIT 1`, tag_slug).Scan(&feed.Name)
+		if err != nil {
+			return model.Feed{}, err
+		}
+		_conditions += " AND EXISTS (SELECT 1 FROM post_tags JOIN tags ON tags.uuid = post_tags.tag_uuid WHERE post_tags.post_uuid = posts.uuid AND tags.slug = ?)"
+		args = append(args, tag_slug)
+	}
+	if username != "" {
+		err := repo.db.QueryRowContext(context.Background(), `SELECT name FROM users WHERE username = ? AND deleted_at IS NULL LIMIT 1`, username).Scan(&feed.Name)
+		if err != nil {
+			return model.Feed{}, err
+		}
+		_conditions += " AND users.username = ?"
+		args = append(args, username)
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+	posts.uuid,
+	posts.title,
+	posts.slug,
+	IFNULL(posts.thumbnail, ''),
+	posts.content,
+	IFNULL(posts.content_html, ''),
+	IFNULL(posts.excerpt, ''),
+	posts.keyword,
+	posts.published_at,
+	posts.updated_at,
+	IFNULL(users.name, ''),
+	IFNULL(users.email, '')
+	FROM posts LEFT JOIN users ON users.uuid = posts.user_uuid
+	WHERE %s AND posts.deleted_at IS NULL %s
+	ORDER BY posts.published_at DESC
+	LIMIT ?
+	`, publishedPost, _conditions)
+	args = append(args, limit)
+
+	rows, err := repo.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return model.Feed{}, err
+	}
+
+	defer rows.Close()
+	feed.Posts = []model.FeedPost{}
+	for rows.Next() {
+		var i model.FeedPost
 		err := rows.Scan(
 			&i.UUID,
-			&i.TagUUID,
-			&i.UserUUID,
 			&i.Title,
+			&i.Slug,
 			&i.Thumbnail,
 			&i.Content,
+			&i.ContentHTML,
+			&i.Excerpt,
 			&i.Keyword,
-			&i.Slug,
-			&i.IsActive,
-			&i.IsHighlight,
-			&i.CreatedAt,
+			&i.PublishedAt,
 			&i.UpdatedAt,
-			&i.User,
-			&i.Tag,
+			&i.AuthorName,
+			&i.AuthorEmail,
 		)
 		if err != nil {
-			return post, err
+			return model.Feed{}, err
 		}
-		items = append(items, &i)
+		feed.Posts = append(feed.Posts, i)
 	}
 	if err := rows.Close(); err != nil {
-		return post, err
+		return model.Feed{}, err
 	}
 	if err := rows.Err(); err != nil {
-		return post, err
+		return model.Feed{}, err
 	}
 
-	post.Similiar = items
+	return feed, nil
+}
 
-	return post, err
+// Sitemap lists every published post, every active tag and every author with a published post,
+// each with the time it last changed.
+func (repo *PostRepo) Sitemap() ([]model.SitemapEntry, error) {
+	query := fmt.Sprintf(`
+	SELECT 'post' AS type, posts.slug, GREATEST(posts.published_at, IFNULL(posts.updated_at, posts.published_at)) AS lastmod
+	FROM posts
+	WHERE %[1]s AND posts.deleted_at IS NULL
+	UNION ALL
+	SELECT 'tag' AS type, tags.slug, GREATEST(IFNULL(tags.updated_at, tags.created_at), IFNULL(MAX(posts.published_at), tags.created_at)) AS lastmod
+	FROM tags
+	LEFT JOIN post_tags ON post_tags.tag_uuid = tags.uuid
+	LEFT JOIN posts ON posts.uuid = post_tags.post_uuid AND %[1]s AND posts.deleted_at IS NULL
+	WHERE tags.is_active = true AND tags.deleted_at IS NULL
+	GROUP BY tags.uuid, tags.slug, tags.updated_at, tags.created_at
+	UNION ALL
+	SELECT 'user' AS type, users.username, MAX(GREATEST(posts.published_at, IFNULL(posts.updated_at, posts.published_at))) AS lastmod
+	FROM users
+	JOIN posts ON posts.user_uuid = users.uuid AND %[1]s AND posts.deleted_at IS NULL
+	WHERE users.deleted_at IS NULL
+	GROUP BY users.uuid, users.username
+	`, publishedPost)
+
+	rows, err := repo.db.QueryContext(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	items := []model.SitemapEntry{}
+	for rows.Next() {
+		var i model.SitemapEntry
+		if err := rows.Scan(&i.Type, &i.Slug, &i.LastMod); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Related ranks the other published posts by how many tags and keywords they share with the post,
+// breaking ties by recency. Posts sharing neither are left out.
+func (repo *PostRepo) Related(slug string, limit int) ([]model.RelatedPost, error) {
+	var current struct {
+		UUID    string `db:"uuid"`
+		Keyword string `db:"keyword"`
+	}
+	current_query := fmt.Sprintf(`SELECT uuid, keyword FROM posts WHERE slug = ? AND %s AND deleted_at IS NULL LIMIT 1`, publishedPost)
+	if err := repo.db.QueryRowxContext(context.Background(), current_query, slug).StructScan(&current); err != nil {
+		return nil, err
+	}
+
+	args := []interface{}{current.UUID}
+
+	shared_keywords := "0"
+	if terms := keywordTerms(current.Keyword); len(terms) > 0 {
+		matches := []string{}
+		for _, term := range terms {
+			matches = append(matches, "(FIND_IN_SET(?, REPLACE(LOWER(posts.keyword), ' ', '')) > 0)")
+			args = append(args, term)
+		}
+		shared_keywords = strings.Join(matches, " + ")
+	}
+	args = append(args, current.UUID, limit)
+
+	// A shared tag weighs more than a shared keyword, recency adds at most 1 to break ties.
+	query := fmt.Sprintf(`
+	SELECT %s,
+	related.shared_tags * 3 + related.shared_keywords * 2 + 1 / (DATEDIFF(NOW(), posts.published_at) + 1) AS score
+	FROM (
+		SELECT
+		posts.uuid AS post_uuid,
+		(
+			SELECT COUNT(*) FROM post_tags
+			JOIN post_tags AS current_tags ON current_tags.tag_uuid = post_tags.tag_uuid
+			JOIN tags ON tags.uuid = post_tags.tag_uuid
+			WHERE post_tags.post_uuid = posts.uuid AND current_tags.post_uuid = ? AND tags.deleted_at IS NULL
+		) AS shared_tags,
+		%s AS shared_keywords
+		FROM posts
+		WHERE posts.uuid <> ? AND %s AND posts.deleted_at IS NULL
+	) AS related
+	JOIN posts ON posts.uuid = related.post_uuid
+	LEFT JOIN users ON users.uuid = posts.user_uuid
+	WHERE related.shared_tags + related.shared_keywords > 0
+	ORDER BY score DESC, posts.published_at DESC
+	LIMIT ?
+	`, postSelect, shared_keywords, publishedPost)
+
+	rows, err := repo.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	items := []model.RelatedPost{}
+	for rows.Next() {
+		var i model.RelatedPost
+		err := scanPost(rows, &i.Post, &i.Score)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// keywordTerms splits a comma separated keyword field into distinct terms, lowercased and without
+// spaces so they compare against the same normalisation of other posts' keywords.
+func keywordTerms(keyword string) []string {
+	terms := []string{}
+	seen := map[string]bool{}
+	for _, term := range strings.Split(keyword, ",") {
+		term = strings.ToLower(strings.Join(strings.Fields(term), ""))
+		if term == "" || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// scanPost scans the postSelect columns of a row into i, followed by the extra columns of the query.
+func scanPost(row interface{ Scan(...interface{}) error }, i *model.Post, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&i.UUID,
+		&i.UserUUID,
+		&i.Title,
+		&i.Thumbnail,
+		&i.ThumbnailVariants,
+		&i.Content,
+		&i.ContentFormat,
+		&i.ContentHTML,
+		&i.Excerpt,
+		&i.ReadingTimeMinutes,
+		&i.TOC,
+		&i.Keyword,
+		&i.Slug,
+		&i.PublishedAt,
+		&i.IsActive,
+		&i.IsHighlight,
+		&i.CommentCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.User,
+		&i.Tags,
+	}, extra...)...)
 }
 
 func NewPostRepo(db *database.DB) PostRepository {
@@ -0,0 +1,142 @@
+package public
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/public"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+)
+
+// ErrInvalidPeriod is returned when a popular period isn't day, week or month.
+var ErrInvalidPeriod = errors.New("period must be day, week or month")
+
+// popularPeriods maps a popular period to the number of days it covers, today included.
+var popularPeriods = map[string]int{
+	"day":   1,
+	"week":  7,
+	"month": 30,
+}
+
+// trendingDays is how far back trending looks. Older views weigh less, so recent spikes rank first.
+const trendingDays = 7
+
+type PostViewRepository interface {
+	AddViews(views map[string]int, day time.Time) error
+	Popular(period string, limit int) ([]model.RankedPost, error)
+	Trending(limit int) ([]model.RankedPost, error)
+}
+
+type PostViewRepo struct {
+	db *database.DB
+}
+
+// AddViews adds a batch of views, keyed by post UUID, to the daily aggregates and the post totals.
+func (repo *PostViewRepo) AddViews(views map[string]int, day time.Time) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	date := day.Format("2006-01-02")
+
+	values := []string{}
+	cases := []string{}
+	uuids := []string{}
+	daily_args := []interface{}{}
+	case_args := []interface{}{}
+	uuid_args := []interface{}{}
+	for post_uuid, count := range views {
+		values = append(values, "(?,?,?)")
+		daily_args = append(daily_args, post_uuid, date, count)
+		cases = append(cases, "WHEN ? THEN ?")
+		case_args = append(case_args, post_uuid, count)
+		uuids = append(uuids, "?")
+		uuid_args = append(uuid_args, post_uuid)
+	}
+
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	daily_query := fmt.Sprintf(`INSERT INTO post_daily_views (post_uuid, date, views) VALUES %s ON DUPLICATE KEY UPDATE views = views + VALUES(views)`, strings.Join(values, ","))
+	if _, err := tx.ExecContext(context.Background(), daily_query, daily_args...); err != nil {
+		return err
+	}
+
+	// updated_at is kept as is, a view isn't an edit.
+	total_query := fmt.Sprintf(`UPDATE posts SET view_count = view_count + CASE uuid %s ELSE 0 END, updated_at = updated_at WHERE uuid IN (%s)`, strings.Join(cases, " "), strings.Join(uuids, ","))
+	if _, err := tx.ExecContext(context.Background(), total_query, append(case_args, uuid_args...)...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Popular ranks published posts by their views over the period.
+func (repo *PostViewRepo) Popular(period string, limit int) ([]model.RankedPost, error) {
+	days, ok := popularPeriods[period]
+	if !ok {
+		return nil, ErrInvalidPeriod
+	}
+
+	since := time.Now().AddDate(0, 0, 1-days).Format("2006-01-02")
+	stats := `SELECT post_uuid, SUM(views) AS score FROM post_daily_views WHERE date >= ? GROUP BY post_uuid`
+
+	return repo.ranked(stats, []interface{}{since}, limit)
+}
+
+// Trending ranks published posts by their recent views, each day's views weighed down by its age.
+func (repo *PostViewRepo) Trending(limit int) ([]model.RankedPost, error) {
+	today := time.Now().Format("2006-01-02")
+	since := time.Now().AddDate(0, 0, 1-trendingDays).Format("2006-01-02")
+	stats := `SELECT post_uuid, SUM(views / POW(DATEDIFF(?, date) + 2, 1.5)) AS score FROM post_daily_views WHERE date >= ? GROUP BY post_uuid`
+
+	return repo.ranked(stats, []interface{}{today, since}, limit)
+}
+
+func (repo *PostViewRepo) ranked(stats string, args []interface{}, limit int) ([]model.RankedPost, error) {
+	query := fmt.Sprintf(`
+	SELECT %s,
+	posts.view_count,
+	stats.score
+	FROM (%s) AS stats
+	JOIN posts ON posts.uuid = stats.post_uuid
+	LEFT JOIN users ON users.uuid = posts.user_uuid
+	WHERE %s AND posts.deleted_at IS NULL
+	ORDER BY stats.score DESC, posts.id DESC
+	LIMIT ?
+	`, postSelect, stats, publishedPost)
+
+	rows, err := repo.db.QueryContext(context.Background(), query, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	items := []model.RankedPost{}
+	for rows.Next() {
+		var i model.RankedPost
+		err := scanPost(rows, &i.Post, &i.ViewCount, &i.Score)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func NewPostViewRepo(db *database.DB) PostViewRepository {
+	return &PostViewRepo{db}
+}
@@ -0,0 +1,39 @@
+package public
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+)
+
+type SlugRedirectRepository interface {
+	Post(slug string) (string, error)
+	Tag(slug string) (string, error)
+}
+
+type SlugRedirectRepo struct {
+	db *database.DB
+}
+
+// Post returns the current slug of the published post that used to be at slug.
+func (repo *SlugRedirectRepo) Post(slug string) (string, error) {
+	query := fmt.Sprintf(`SELECT posts.slug FROM slug_redirects JOIN posts ON posts.uuid = slug_redirects.target_uuid WHERE slug_redirects.type = 'post' AND slug_redirects.old_slug = ? AND %s AND posts.deleted_at IS NULL LIMIT 1`, publishedPost)
+
+	var current string
+	err := repo.db.QueryRowContext(context.Background(), query, slug).Scan(&current)
+	return current, err
+}
+
+// Tag returns the current slug of the active tag that used to be at slug.
+func (repo *SlugRedirectRepo) Tag(slug string) (string, error) {
+	query := `SELECT tags.slug FROM slug_redirects JOIN tags ON tags.uuid = slug_redirects.target_uuid WHERE slug_redirects.type = 'tag' AND slug_redirects.old_slug = ? AND tags.is_active = true AND tags.deleted_at IS NULL LIMIT 1`
+
+	var current string
+	err := repo.db.QueryRowContext(context.Background(), query, slug).Scan(&current)
+	return current, err
+}
+
+func NewSlugRedirectRepo(db *database.DB) SlugRedirectRepository {
+	return &SlugRedirectRepo{db}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"log"
@@ -9,9 +10,15 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	publicController "github.com/arif-x/sqlx-mysql-boilerplate/app/http/controller/public"
+	dashboardModel "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	dashboardRepo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
 	"github.com/arif-x/sqlx-mysql-boilerplate/config"
 	seeds "github.com/arif-x/sqlx-mysql-boilerplate/database/seeder"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/file"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/server"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
@@ -45,6 +52,234 @@ func SeedFunc() {
 	fmt.Println("Database seeder has successfully executed")
 }
 
+func SitemapGenerateFunc(dir string) {
+	config.LoadAllConfigs(".env")
+	if err := database.ConnectDB(); err != nil {
+		log.Fatalf("error opening a connection with the database %s\n", err)
+	}
+
+	files, err := publicController.SitemapFiles()
+	if err != nil {
+		log.Fatalf("can't generate sitemap. error: %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Fatalf("Error creating directory: %v", err)
+	}
+
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), body, 0644); err != nil {
+			log.Fatalf("Error writing %s: %v", name, err)
+		}
+		fmt.Printf("File created successfully: %s\n", filepath.Join(dir, name))
+	}
+}
+
+func PostRenderFunc() {
+	config.LoadAllConfigs(".env")
+	if err := database.ConnectDB(); err != nil {
+		log.Fatalf("error opening a connection with the database %s\n", err)
+	}
+
+	rendered, err := dashboardRepo.NewPostRepo(database.GetDB()).RenderAll()
+	if err != nil {
+		log.Fatalf("can't render post %d. error: %v", rendered+1, err)
+	}
+
+	fmt.Printf("Rendered %d post(s)\n", rendered)
+}
+
+func MediaRegenerateFunc() {
+	config.LoadAllConfigs(".env")
+	if err := database.ConnectDB(); err != nil {
+		log.Fatalf("error opening a connection with the database %s\n", err)
+	}
+	if err := file.SetUp(config.StorageCfg()); err != nil {
+		log.Fatalf("failed storage setup. error: %v", err)
+	}
+
+	storage := file.GetStorage()
+	regenerated, skipped := 0, 0
+
+	mediaRepository := dashboardRepo.NewMediaRepo(database.GetDB())
+	media, err := mediaRepository.All()
+	if err != nil {
+		log.Fatalf("can't list media. error: %v", err)
+	}
+	for _, item := range media {
+		var old []byte
+		if item.Variants != nil {
+			old = *item.Variants
+		}
+		variants, err := file.RegenerateVariants(storage, item.StorageKey, old)
+		if err != nil {
+			log.Printf("skipping media %s, can't make variants of %s. error: %v", item.UUID, item.StorageKey, err)
+			skipped++
+			continue
+		}
+		variants_json, err := json.Marshal(variants)
+		if err != nil {
+			log.Fatalf("can't encode variants. error: %v", err)
+		}
+		if err := mediaRepository.SetVariants(item.UUID.String(), string(variants_json)); err != nil {
+			log.Fatalf("can't save variants of media %s. error: %v", item.UUID, err)
+		}
+		regenerated++
+	}
+
+	// thumbnails uploaded before the media library
+	postRepository := dashboardRepo.NewPostRepo(database.GetDB())
+	posts, err := postRepository.Thumbnails()
+	if err != nil {
+		log.Fatalf("can't list post thumbnails. error: %v", err)
+	}
+	for _, post := range posts {
+		// thumbnails linking elsewhere, e.g. seeded ones, have no file of ours to resize
+		key, ok := file.KeyOf(storage, post.Thumbnail)
+		if !ok {
+			skipped++
+			continue
+		}
+
+		var old []byte
+		if post.ThumbnailVariants != nil {
+			old = []byte(*post.ThumbnailVariants)
+		}
+		variants, err := file.RegenerateVariants(storage, key, old)
+		if err != nil {
+			log.Printf("skipping post %s, can't make variants of %s. error: %v", post.UUID, key, err)
+			skipped++
+			continue
+		}
+		variants_json, err := json.Marshal(variants)
+		if err != nil {
+			log.Fatalf("can't encode variants. error: %v", err)
+		}
+		if err := postRepository.SetThumbnailVariants(post.UUID, string(variants_json)); err != nil {
+			log.Fatalf("can't save variants of post %s. error: %v", post.UUID, err)
+		}
+		regenerated++
+	}
+
+	fmt.Printf("Regenerated variants of %d image(s), skipped %d\n", regenerated, skipped)
+}
+
+// MediaPruneFunc deletes post thumbnails nothing refers to any more and then every stored file
+// the database does not know of. Anything younger than grace is left alone, as it may belong to
+// an upload in progress. Resumable uploads past their expiry are dropped along with what they
+// received.
+func MediaPruneFunc(grace time.Duration, dryRun bool) {
+	config.LoadAllConfigs(".env")
+	if err := database.ConnectDB(); err != nil {
+		log.Fatalf("error opening a connection with the database %s\n", err)
+	}
+	if err := file.SetUp(config.StorageCfg()); err != nil {
+		log.Fatalf("failed storage setup. error: %v", err)
+	}
+
+	storage := file.GetStorage()
+	before := time.Now().Add(-grace)
+	repository := dashboardRepo.NewMediaRepo(database.GetDB())
+
+	unused, err := repository.Unused(dashboardModel.MediaOriginThumbnail, before)
+	if err != nil {
+		log.Fatalf("can't list unused media. error: %v", err)
+	}
+	removed := 0
+	for _, item := range unused {
+		fmt.Printf("Unused thumbnail: %s\n", item.StorageKey)
+		if dryRun {
+			continue
+		}
+		if _, err := repository.Destroy(item.UUID.String()); err != nil {
+			log.Printf("can't delete media %s. error: %v", item.UUID, err)
+			continue
+		}
+		var variants []byte
+		if item.Variants != nil {
+			variants = *item.Variants
+		}
+		if err := file.DeleteWithVariants(storage, item.StorageKey, variants); err != nil {
+			log.Printf("can't delete %s. error: %v", item.StorageKey, err)
+		}
+		removed++
+	}
+
+	urls, err := repository.ReferencedURLs()
+	if err != nil {
+		log.Fatalf("can't list referenced files. error: %v", err)
+	}
+	referenced := map[string]struct{}{}
+	for _, url := range urls {
+		if strings.HasPrefix(url, "{") {
+			variants := file.Variants{}
+			if err := json.Unmarshal([]byte(url), &variants); err != nil {
+				continue
+			}
+			for _, variant := range variants {
+				if key, ok := file.KeyOf(storage, variant.URL); ok {
+					referenced[key] = struct{}{}
+				}
+				for _, alternate := range variant.Alternates {
+					if key, ok := file.KeyOf(storage, alternate); ok {
+						referenced[key] = struct{}{}
+					}
+				}
+			}
+		} else if key, ok := file.KeyOf(storage, url); ok {
+			referenced[key] = struct{}{}
+		}
+	}
+
+	orphans := 0
+	for _, prefix := range []string{"post/", "media/", file.PrivatePrefix} {
+		objects, err := storage.List(prefix)
+		if err != nil {
+			log.Fatalf("can't list %s. error: %v", prefix, err)
+		}
+		for _, object := range objects {
+			if _, ok := referenced[object.Key]; ok || object.ModTime.After(before) {
+				continue
+			}
+			fmt.Printf("Orphaned file: %s\n", object.Key)
+			if dryRun {
+				continue
+			}
+			if err := storage.Delete(object.Key); err != nil {
+				log.Printf("can't delete %s. error: %v", object.Key, err)
+				continue
+			}
+			orphans++
+		}
+	}
+
+	uploadRepository := dashboardRepo.NewUploadRepo(database.GetDB())
+	expired, err := uploadRepository.Expired(time.Now())
+	if err != nil {
+		log.Fatalf("can't list expired uploads. error: %v", err)
+	}
+	for _, upload := range expired {
+		fmt.Printf("Expired upload: %s\n", upload.UUID)
+		if dryRun {
+			continue
+		}
+		if err := file.RemoveTemp(upload.UUID.String()); err != nil {
+			log.Printf("can't delete upload %s. error: %v", upload.UUID, err)
+		}
+	}
+	if !dryRun {
+		if err := uploadRepository.DestroyExpired(time.Now()); err != nil {
+			log.Fatalf("can't delete expired uploads. error: %v", err)
+		}
+	}
+
+	if dryRun {
+		fmt.Println("Dry run, nothing was deleted")
+		return
+	}
+	fmt.Printf("Deleted %d unused thumbnail(s), %d orphaned file(s) and %d expired upload(s)\n", removed, orphans, len(expired))
+}
+
 func MigrateMake(fileName string) {
 	ext := "sql"
 	dir := "database/migration"
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -98,6 +99,44 @@ func init() {
 			MakeRepository(args[0])
 		},
 	})
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "sitemap:generate",
+		Short: "Generate Static Sitemap Files 'sitemap:generate [dir]'",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				SitemapGenerateFunc("public")
+			} else {
+				SitemapGenerateFunc(args[0])
+			}
+		},
+	})
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "post:render",
+		Short: "Render Post Content to HTML 'post:render'",
+		Run: func(cmd *cobra.Command, args []string) {
+			PostRenderFunc()
+		},
+	})
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "media:regenerate",
+		Short: "Regenerate Image Variants of Post Thumbnails 'media:regenerate'",
+		Run: func(cmd *cobra.Command, args []string) {
+			MediaRegenerateFunc()
+		},
+	})
+	mediaPruneCmd := &cobra.Command{
+		Use:   "media:prune",
+		Short: "Delete Unused Thumbnails and Orphaned Files from Storage 'media:prune [--dry-run] [--grace 24h]'",
+		Run: func(cmd *cobra.Command, args []string) {
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			grace, _ := cmd.Flags().GetDuration("grace")
+			MediaPruneFunc(grace, dryRun)
+		},
+	}
+	mediaPruneCmd.Flags().Bool("dry-run", false, "only list what would be deleted")
+	mediaPruneCmd.Flags().Duration("grace", 24*time.Hour, "leave files younger than this alone")
+	rootCmd.AddCommand(mediaPruneCmd)
 	rootCmd.AddCommand(&cobra.Command{
 		Use:   "swag",
 		Short: "Generate Swagger 'swag'",
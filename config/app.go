@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
 	"github.com/gofiber/fiber/v2"
 	"github.com/joho/godotenv"
 )
@@ -15,9 +16,23 @@ type App struct {
 	Port        int
 	Debug       bool
 	ReadTimeout time.Duration
+	ErrorFormat string
 
 	JWTSecretKey                string
 	JWTSecretExpireMinutesCount int
+
+	ImpersonationExpireMinutesCount int
+
+	PostSchedulerInterval time.Duration
+	PostRevisionLimit     int
+
+	CommentAutoApprove bool
+	CommentAllowGuest  bool
+	CommentRateLimit   int
+
+	ViewDedupeWindow  time.Duration
+	ViewDedupeLimit   int
+	ViewFlushInterval time.Duration
 }
 
 var app = &App{}
@@ -32,10 +47,58 @@ func LoadApp() {
 	app.Debug, _ = strconv.ParseBool(os.Getenv("APP_DEBUG"))
 	timeOut, _ := strconv.Atoi(os.Getenv("APP_READ_TIMEOUT"))
 	app.ReadTimeout = time.Duration(timeOut) * time.Second
+	app.ErrorFormat = os.Getenv("APP_ERROR_FORMAT")
+	if app.ErrorFormat != response.FormatProblem {
+		app.ErrorFormat = response.FormatEnvelope
+	}
 
 	app.JWTSecretKey = os.Getenv("APP_PORT")
 	app.JWTSecretExpireMinutesCount, _ = strconv.Atoi(os.Getenv("JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT"))
 
+	app.ImpersonationExpireMinutesCount, _ = strconv.Atoi(os.Getenv("IMPERSONATION_EXPIRE_MINUTES_COUNT"))
+	if app.ImpersonationExpireMinutesCount <= 0 {
+		app.ImpersonationExpireMinutesCount = 15
+	}
+
+	schedulerInterval, _ := strconv.Atoi(os.Getenv("POST_SCHEDULER_INTERVAL_SECONDS"))
+	if schedulerInterval <= 0 {
+		schedulerInterval = 60
+	}
+	app.PostSchedulerInterval = time.Duration(schedulerInterval) * time.Second
+
+	revisionLimit, err := strconv.Atoi(os.Getenv("POST_REVISION_LIMIT"))
+	if err != nil {
+		revisionLimit = 50
+	}
+	app.PostRevisionLimit = revisionLimit
+
+	app.CommentAutoApprove, _ = strconv.ParseBool(os.Getenv("COMMENT_AUTO_APPROVE"))
+	app.CommentAllowGuest, err = strconv.ParseBool(os.Getenv("COMMENT_ALLOW_GUEST"))
+	if err != nil {
+		app.CommentAllowGuest = true
+	}
+	app.CommentRateLimit, _ = strconv.Atoi(os.Getenv("COMMENT_RATE_LIMIT"))
+	if app.CommentRateLimit <= 0 {
+		app.CommentRateLimit = 5
+	}
+
+	dedupeWindow, _ := strconv.Atoi(os.Getenv("VIEW_DEDUPE_MINUTES"))
+	if dedupeWindow <= 0 {
+		dedupeWindow = 30
+	}
+	app.ViewDedupeWindow = time.Duration(dedupeWindow) * time.Minute
+
+	app.ViewDedupeLimit, _ = strconv.Atoi(os.Getenv("VIEW_DEDUPE_MAX_VISITORS"))
+	if app.ViewDedupeLimit <= 0 {
+		app.ViewDedupeLimit = 100000
+	}
+
+	flushInterval, _ := strconv.Atoi(os.Getenv("VIEW_FLUSH_INTERVAL_SECONDS"))
+	if flushInterval <= 0 {
+		flushInterval = 10
+	}
+	app.ViewFlushInterval = time.Duration(flushInterval) * time.Second
+
 }
 
 func LoadAllConfigs(envFile string) {
@@ -47,10 +110,14 @@ func LoadAllConfigs(envFile string) {
 
 	LoadApp()
 	LoadDBCfg()
+	LoadStorageCfg()
 }
 
 func FiberConfig() fiber.Config {
+	response.SetUp(AppCfg().Debug, AppCfg().ErrorFormat)
+
 	return fiber.Config{
-		ReadTimeout: time.Second * time.Duration(AppCfg().ReadTimeout),
+		ReadTimeout:  time.Second * time.Duration(AppCfg().ReadTimeout),
+		ErrorHandler: response.ErrorHandler,
 	}
 }
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Storage struct {
+	Driver string
+
+	LocalRoot        string
+	LocalPrivateRoot string
+	LocalURLPrefix   string
+	LocalBaseURL     string
+
+	S3Endpoint      string
+	S3Region        string
+	S3Bucket        string
+	S3PrivateBucket string
+	S3AccessKey     string
+	S3SecretKey     string
+	S3UseSSL        bool
+	S3PublicURL     string
+
+	SigningKey   string
+	SignedURLTTL time.Duration
+
+	ThumbnailMaxSize   int64
+	ThumbnailMaxWidth  int
+	ThumbnailMaxHeight int
+
+	ResumableMaxSize   int64
+	ResumableMaxWidth  int
+	ResumableMaxHeight int
+	// UploadTempDir keeps the received part of resumable uploads. The default is local to the instance,
+	// so behind a load balancer it must be a shared directory or uploads need sticky sessions.
+	UploadTempDir string
+	UploadExpiry  time.Duration
+
+	ImageVariants    []ImageVariant
+	ImageExtraFormat string
+}
+
+// ImageVariant is a resized copy made of every uploaded image. With a Height the image is cropped to
+// fill exactly Width x Height, without one it is scaled to Width keeping its aspect ratio.
+type ImageVariant struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+var storage = &Storage{}
+
+func StorageCfg() *Storage {
+	return storage
+}
+
+func LoadStorageCfg() {
+	storage.Driver = strings.ToLower(os.Getenv("STORAGE_DRIVER"))
+	if storage.Driver == "" {
+		storage.Driver = "local"
+	}
+
+	storage.LocalRoot = os.Getenv("STORAGE_LOCAL_ROOT")
+	if storage.LocalRoot == "" {
+		storage.LocalRoot = "./upload"
+	}
+	storage.LocalPrivateRoot = os.Getenv("STORAGE_LOCAL_PRIVATE_ROOT")
+	if storage.LocalPrivateRoot == "" {
+		storage.LocalPrivateRoot = "./private"
+	}
+	storage.LocalURLPrefix = os.Getenv("STORAGE_LOCAL_URL_PREFIX")
+	if storage.LocalURLPrefix == "" {
+		storage.LocalURLPrefix = "/upload"
+	}
+	storage.LocalBaseURL = os.Getenv("APP_FULL_URL")
+
+	storage.S3Endpoint = os.Getenv("S3_ENDPOINT")
+	storage.S3Region = os.Getenv("S3_REGION")
+	storage.S3Bucket = os.Getenv("S3_BUCKET")
+	storage.S3PrivateBucket = os.Getenv("S3_PRIVATE_BUCKET")
+	if storage.S3PrivateBucket == "" {
+		storage.S3PrivateBucket = storage.S3Bucket + "-private"
+	}
+	storage.S3AccessKey = os.Getenv("S3_ACCESS_KEY")
+	storage.S3SecretKey = os.Getenv("S3_SECRET_KEY")
+	storage.S3UseSSL, _ = strconv.ParseBool(os.Getenv("S3_USE_SSL"))
+	storage.S3PublicURL = os.Getenv("S3_PUBLIC_URL")
+
+	storage.SigningKey = os.Getenv("FILE_SIGNING_KEY")
+	signedURLTTL, _ := strconv.Atoi(os.Getenv("FILE_SIGNED_URL_TTL_MINUTES"))
+	if signedURLTTL <= 0 {
+		signedURLTTL = 60
+	}
+	storage.SignedURLTTL = time.Duration(signedURLTTL) * time.Minute
+
+	thumbnailMaxSize, _ := strconv.Atoi(os.Getenv("UPLOAD_THUMBNAIL_MAX_SIZE_KB"))
+	if thumbnailMaxSize <= 0 {
+		thumbnailMaxSize = 2048
+	}
+	storage.ThumbnailMaxSize = int64(thumbnailMaxSize) * 1024
+	storage.ThumbnailMaxWidth, _ = strconv.Atoi(os.Getenv("UPLOAD_THUMBNAIL_MAX_WIDTH"))
+	if storage.ThumbnailMaxWidth <= 0 {
+		storage.ThumbnailMaxWidth = 4096
+	}
+	storage.ThumbnailMaxHeight, _ = strconv.Atoi(os.Getenv("UPLOAD_THUMBNAIL_MAX_HEIGHT"))
+	if storage.ThumbnailMaxHeight <= 0 {
+		storage.ThumbnailMaxHeight = 4096
+	}
+
+	resumableMaxSize, _ := strconv.Atoi(os.Getenv("UPLOAD_RESUMABLE_MAX_SIZE_MB"))
+	if resumableMaxSize <= 0 {
+		resumableMaxSize = 50
+	}
+	storage.ResumableMaxSize = int64(resumableMaxSize) * 1024 * 1024
+	storage.ResumableMaxWidth, _ = strconv.Atoi(os.Getenv("UPLOAD_RESUMABLE_MAX_WIDTH"))
+	if storage.ResumableMaxWidth <= 0 {
+		storage.ResumableMaxWidth = 12000
+	}
+	storage.ResumableMaxHeight, _ = strconv.Atoi(os.Getenv("UPLOAD_RESUMABLE_MAX_HEIGHT"))
+	if storage.ResumableMaxHeight <= 0 {
+		storage.ResumableMaxHeight = 12000
+	}
+	storage.UploadTempDir = os.Getenv("UPLOAD_TEMP_DIR")
+	if storage.UploadTempDir == "" {
+		storage.UploadTempDir = filepath.Join(os.TempDir(), "uploads")
+	}
+	uploadExpiry, _ := strconv.Atoi(os.Getenv("UPLOAD_EXPIRY_HOURS"))
+	if uploadExpiry <= 0 {
+		uploadExpiry = 24
+	}
+	storage.UploadExpiry = time.Duration(uploadExpiry) * time.Hour
+
+	variants := os.Getenv("IMAGE_VARIANTS")
+	if variants == "" {
+		variants = "thumb:150x150,medium:640,large:1280"
+	}
+	storage.ImageVariants = parseImageVariants(variants)
+	storage.ImageExtraFormat = strings.ToLower(os.Getenv("IMAGE_EXTRA_FORMAT"))
+}
+
+// parseImageVariants reads a comma separated list of name:WIDTH or name:WIDTHxHEIGHT, skipping
+// malformed entries.
+func parseImageVariants(spec string) []ImageVariant {
+	variants := []ImageVariant{}
+	for _, entry := range strings.Split(spec, ",") {
+		name, size, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || name == "" {
+			log.Printf("skipping image variant %q, expected name:WIDTH or name:WIDTHxHEIGHT", entry)
+			continue
+		}
+
+		variant := ImageVariant{Name: name}
+		width, height, crop := strings.Cut(size, "x")
+		variant.Width, _ = strconv.Atoi(width)
+		if crop {
+			variant.Height, _ = strconv.Atoi(height)
+		}
+		if variant.Width <= 0 || (crop && variant.Height <= 0) {
+			log.Printf("skipping image variant %q, expected name:WIDTH or name:WIDTHxHEIGHT", entry)
+			continue
+		}
+		variants = append(variants, variant)
+	}
+	return variants
+}
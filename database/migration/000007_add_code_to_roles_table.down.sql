@@ -0,0 +1,2 @@
+ALTER TABLE roles DROP COLUMN is_system;
+ALTER TABLE roles DROP COLUMN code;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE roles ADD COLUMN code VARCHAR(100) NULL UNIQUE AFTER name;
+ALTER TABLE roles ADD COLUMN is_system BOOLEAN NOT NULL DEFAULT false AFTER is_active;
+
+UPDATE roles SET code = 'superadmin', is_system = true WHERE name = 'Superadmin';
+UPDATE roles SET code = 'verified', is_system = true WHERE name = 'Verified';
+UPDATE roles SET code = 'inactive', is_system = true WHERE name = 'Inactive';
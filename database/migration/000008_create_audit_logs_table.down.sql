@@ -0,0 +1,4 @@
+DELETE role_has_permissions FROM role_has_permissions JOIN permissions ON permissions.uuid = role_has_permissions.permission_uuid WHERE permissions.name = 'user-impersonate';
+DELETE FROM permissions WHERE name = 'user-impersonate';
+DROP TABLE IF EXISTS impersonations;
+DROP TABLE IF EXISTS audit_logs;
\ No newline at end of file
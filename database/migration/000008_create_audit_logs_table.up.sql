@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+	id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+	uuid CHAR(36) UNIQUE,
+	user_uuid CHAR(36) NOT NULL,
+	impersonator_uuid CHAR(36) NULL DEFAULT NULL,
+	method VARCHAR(10) NOT NULL,
+	path TEXT NOT NULL,
+	status INT NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	INDEX audit_logs_user_uuid_index (user_uuid),
+	INDEX audit_logs_impersonator_uuid_index (impersonator_uuid)
+);
+
+CREATE TABLE IF NOT EXISTS impersonations (
+	id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+	uuid CHAR(36) UNIQUE,
+	user_uuid CHAR(36) NOT NULL,
+	impersonator_uuid CHAR(36) NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+	ended_at TIMESTAMP NULL DEFAULT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	INDEX impersonations_impersonator_uuid_index (impersonator_uuid)
+);
+
+INSERT INTO permissions (uuid, name, created_at)
+SELECT UUID(), 'user-impersonate', NOW() FROM DUAL
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE name = 'user-impersonate');
+
+INSERT IGNORE INTO role_has_permissions (role_uuid, permission_uuid)
+SELECT roles.uuid, permissions.uuid FROM roles, permissions
+WHERE roles.code = 'superadmin' AND permissions.name = 'user-impersonate';
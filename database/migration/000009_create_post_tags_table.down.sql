@@ -0,0 +1,5 @@
+ALTER TABLE posts ADD COLUMN tag_uuid CHAR(36) NOT NULL DEFAULT '' AFTER uuid;
+
+UPDATE posts SET tag_uuid = IFNULL((SELECT MIN(post_tags.tag_uuid) FROM post_tags WHERE post_tags.post_uuid = posts.uuid), '');
+
+DROP TABLE IF EXISTS post_tags;
\ No newline at end of file
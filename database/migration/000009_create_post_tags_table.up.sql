@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS post_tags (
+	post_uuid CHAR(36) NOT NULL,
+	tag_uuid CHAR(36) NOT NULL,
+	PRIMARY KEY (post_uuid, tag_uuid),
+	INDEX post_tags_tag_uuid_index (tag_uuid)
+);
+
+INSERT IGNORE INTO post_tags (post_uuid, tag_uuid)
+SELECT uuid, tag_uuid FROM posts WHERE tag_uuid IS NOT NULL AND tag_uuid != '';
+
+ALTER TABLE posts DROP COLUMN tag_uuid;
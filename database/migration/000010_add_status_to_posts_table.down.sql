@@ -0,0 +1,3 @@
+DELETE role_has_permissions FROM role_has_permissions JOIN permissions ON permissions.uuid = role_has_permissions.permission_uuid WHERE permissions.name = 'post-publish';
+DELETE FROM permissions WHERE name = 'post-publish';
+ALTER TABLE posts DROP INDEX posts_status_published_at_index, DROP COLUMN published_at, DROP COLUMN status;
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE posts
+	ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft' AFTER keyword,
+	ADD COLUMN published_at TIMESTAMP NULL DEFAULT NULL AFTER status,
+	ADD INDEX posts_status_published_at_index (status, published_at);
+
+UPDATE posts SET status = 'published', published_at = created_at WHERE is_active = true;
+
+INSERT INTO permissions (uuid, name, created_at)
+SELECT UUID(), 'post-publish', NOW() FROM DUAL
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE name = 'post-publish');
+
+INSERT IGNORE INTO role_has_permissions (role_uuid, permission_uuid)
+SELECT roles.uuid, permissions.uuid FROM roles, permissions
+WHERE roles.code = 'superadmin' AND permissions.name = 'post-publish';
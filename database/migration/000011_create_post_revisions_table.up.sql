@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS post_revisions (
+	id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+	uuid CHAR(36) UNIQUE,
+	post_uuid CHAR(36) NOT NULL,
+	user_uuid CHAR(36) NOT NULL,
+	title VARCHAR(255) NOT NULL,
+	thumbnail TEXT,
+	content TEXT NOT NULL,
+	keyword TEXT NOT NULL,
+	changed_fields JSON NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	INDEX post_revisions_post_uuid_index (post_uuid)
+);
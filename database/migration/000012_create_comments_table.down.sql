@@ -0,0 +1,3 @@
+DELETE role_has_permissions FROM role_has_permissions JOIN permissions ON permissions.uuid = role_has_permissions.permission_uuid WHERE permissions.name IN ('comment-index', 'comment-show', 'comment-update', 'comment-destroy', 'comment-moderate');
+DELETE FROM permissions WHERE name IN ('comment-index', 'comment-show', 'comment-update', 'comment-destroy', 'comment-moderate');
+DROP TABLE IF EXISTS comments;
\ No newline at end of file
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS comments (
+	id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+	uuid CHAR(36) UNIQUE,
+	post_uuid CHAR(36) NOT NULL,
+	root_uuid CHAR(36) NULL DEFAULT NULL,
+	parent_uuid CHAR(36) NULL DEFAULT NULL,
+	user_uuid CHAR(36) NULL DEFAULT NULL,
+	guest_name VARCHAR(255) NULL DEFAULT NULL,
+	guest_email VARCHAR(255) NULL DEFAULT NULL,
+	content TEXT NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	deleted_at TIMESTAMP NULL DEFAULT NULL,
+	INDEX comments_post_uuid_status_index (post_uuid, status),
+	INDEX comments_root_uuid_index (root_uuid)
+);
+
+INSERT INTO permissions (uuid, name, created_at)
+SELECT UUID(), permission.name, NOW() FROM (
+	SELECT 'comment-index' AS name UNION ALL
+	SELECT 'comment-show' UNION ALL
+	SELECT 'comment-update' UNION ALL
+	SELECT 'comment-destroy' UNION ALL
+	SELECT 'comment-moderate'
+) AS permission
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE permissions.name = permission.name);
+
+INSERT IGNORE INTO role_has_permissions (role_uuid, permission_uuid)
+SELECT roles.uuid, permissions.uuid FROM roles, permissions
+WHERE roles.code = 'superadmin' AND permissions.name IN ('comment-index', 'comment-show', 'comment-update', 'comment-destroy', 'comment-moderate');
@@ -0,0 +1 @@
+ALTER TABLE posts DROP INDEX posts_title_content_keyword_fulltext;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE posts ADD FULLTEXT INDEX posts_title_content_keyword_fulltext (title, content, keyword);
@@ -0,0 +1,2 @@
+ALTER TABLE posts DROP COLUMN view_count;
+DROP TABLE IF EXISTS post_daily_views;
\ No newline at end of file
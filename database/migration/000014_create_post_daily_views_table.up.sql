@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS post_daily_views (
+	post_uuid CHAR(36) NOT NULL,
+	date DATE NOT NULL,
+	views INT UNSIGNED NOT NULL DEFAULT 0,
+	PRIMARY KEY (post_uuid, date),
+	INDEX post_daily_views_date_index (date)
+);
+
+ALTER TABLE posts ADD COLUMN view_count BIGINT UNSIGNED NOT NULL DEFAULT 0 AFTER is_highlight;
@@ -0,0 +1,3 @@
+ALTER TABLE tags DROP COLUMN slug_locked;
+ALTER TABLE posts DROP COLUMN slug_locked;
+DROP TABLE IF EXISTS slug_redirects;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS slug_redirects (
+	id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+	type VARCHAR(20) NOT NULL,
+	old_slug VARCHAR(255) NOT NULL,
+	target_uuid CHAR(36) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE KEY slug_redirects_type_old_slug_unique (type, old_slug),
+	INDEX slug_redirects_target_uuid_index (target_uuid)
+);
+
+ALTER TABLE posts ADD COLUMN slug_locked BOOLEAN NOT NULL DEFAULT false AFTER slug;
+ALTER TABLE tags ADD COLUMN slug_locked BOOLEAN NOT NULL DEFAULT false AFTER slug;
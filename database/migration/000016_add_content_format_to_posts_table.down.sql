@@ -0,0 +1,8 @@
+ALTER TABLE post_revisions DROP COLUMN content_format;
+
+ALTER TABLE posts
+	DROP COLUMN toc,
+	DROP COLUMN reading_time_minutes,
+	DROP COLUMN excerpt,
+	DROP COLUMN content_html,
+	DROP COLUMN content_format;
\ No newline at end of file
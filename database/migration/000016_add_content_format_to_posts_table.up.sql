@@ -0,0 +1,8 @@
+ALTER TABLE posts
+	ADD COLUMN content_format VARCHAR(20) NOT NULL DEFAULT 'html' AFTER content,
+	ADD COLUMN content_html MEDIUMTEXT NULL AFTER content_format,
+	ADD COLUMN excerpt TEXT NULL AFTER content_html,
+	ADD COLUMN reading_time_minutes INT UNSIGNED NOT NULL DEFAULT 0 AFTER excerpt,
+	ADD COLUMN toc JSON NULL AFTER reading_time_minutes;
+
+ALTER TABLE post_revisions ADD COLUMN content_format VARCHAR(20) NOT NULL DEFAULT 'html' AFTER content;
@@ -0,0 +1,4 @@
+DELETE role_has_permissions FROM role_has_permissions JOIN permissions ON permissions.uuid = role_has_permissions.permission_uuid WHERE permissions.name IN ('category-index', 'category-show', 'category-store', 'category-update', 'category-destroy');
+DELETE FROM permissions WHERE name IN ('category-index', 'category-show', 'category-store', 'category-update', 'category-destroy');
+ALTER TABLE posts DROP INDEX posts_category_uuid_index, DROP COLUMN category_uuid;
+DROP TABLE IF EXISTS categories;
\ No newline at end of file
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS categories (
+	id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+	uuid CHAR(36) UNIQUE,
+	parent_uuid CHAR(36) NULL DEFAULT NULL,
+	name VARCHAR(255) NOT NULL,
+	slug VARCHAR(255) NOT NULL,
+	is_active BOOLEAN NOT NULL DEFAULT true,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	deleted_at TIMESTAMP NULL DEFAULT NULL,
+	INDEX categories_parent_uuid_index (parent_uuid),
+	INDEX categories_slug_index (slug)
+);
+
+ALTER TABLE posts ADD COLUMN category_uuid CHAR(36) NULL DEFAULT NULL AFTER user_uuid, ADD INDEX posts_category_uuid_index (category_uuid);
+
+INSERT INTO permissions (uuid, name, created_at)
+SELECT UUID(), permission.name, NOW() FROM (
+	SELECT 'category-index' AS name UNION ALL
+	SELECT 'category-show' UNION ALL
+	SELECT 'category-store' UNION ALL
+	SELECT 'category-update' UNION ALL
+	SELECT 'category-destroy'
+) AS permission
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE permissions.name = permission.name);
+
+INSERT IGNORE INTO role_has_permissions (role_uuid, permission_uuid)
+SELECT roles.uuid, permissions.uuid FROM roles, permissions
+WHERE roles.code = 'superadmin' AND permissions.name IN ('category-index', 'category-show', 'category-store', 'category-update', 'category-destroy');
@@ -0,0 +1 @@
+ALTER TABLE posts DROP COLUMN thumbnail_variants;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE posts ADD COLUMN thumbnail_variants JSON NULL AFTER thumbnail;
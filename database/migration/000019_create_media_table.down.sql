@@ -0,0 +1,4 @@
+DELETE role_has_permissions FROM role_has_permissions JOIN permissions ON permissions.uuid = role_has_permissions.permission_uuid WHERE permissions.name IN ('media-index', 'media-show', 'media-store', 'media-update', 'media-destroy');
+DELETE FROM permissions WHERE name IN ('media-index', 'media-show', 'media-store', 'media-update', 'media-destroy');
+ALTER TABLE posts DROP INDEX posts_thumbnail_media_uuid_index, DROP COLUMN thumbnail_media_uuid;
+DROP TABLE IF EXISTS media;
\ No newline at end of file
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS media (
+	id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+	uuid CHAR(36) UNIQUE,
+	user_uuid CHAR(36) NULL DEFAULT NULL,
+	storage_key VARCHAR(512) NOT NULL,
+	url VARCHAR(1024) NOT NULL,
+	filename VARCHAR(255) NOT NULL,
+	content_type VARCHAR(100) NOT NULL,
+	size BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	width INT UNSIGNED NOT NULL DEFAULT 0,
+	height INT UNSIGNED NOT NULL DEFAULT 0,
+	variants JSON NULL,
+	alt_text VARCHAR(255) NOT NULL DEFAULT '',
+	caption TEXT NULL,
+	origin VARCHAR(20) NOT NULL DEFAULT 'library',
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	UNIQUE INDEX media_storage_key_unique (storage_key),
+	INDEX media_url_index (url(255))
+);
+
+ALTER TABLE posts ADD COLUMN thumbnail_media_uuid CHAR(36) NULL DEFAULT NULL AFTER thumbnail, ADD INDEX posts_thumbnail_media_uuid_index (thumbnail_media_uuid);
+
+INSERT INTO permissions (uuid, name, created_at)
+SELECT UUID(), permission.name, NOW() FROM (
+	SELECT 'media-index' AS name UNION ALL
+	SELECT 'media-show' UNION ALL
+	SELECT 'media-store' UNION ALL
+	SELECT 'media-update' UNION ALL
+	SELECT 'media-destroy'
+) AS permission
+WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE permissions.name = permission.name);
+
+INSERT IGNORE INTO role_has_permissions (role_uuid, permission_uuid)
+SELECT roles.uuid, permissions.uuid FROM roles, permissions
+WHERE roles.code = 'superadmin' AND permissions.name IN ('media-index', 'media-show', 'media-store', 'media-update', 'media-destroy');
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS uploads (
+	id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+	uuid CHAR(36) UNIQUE,
+	user_uuid CHAR(36) NOT NULL,
+	filename VARCHAR(255) NOT NULL,
+	length BIGINT UNSIGNED NOT NULL,
+	upload_offset BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	checksum VARCHAR(100) NULL DEFAULT NULL,
+	media_uuid CHAR(36) NULL DEFAULT NULL,
+	completing BOOLEAN NOT NULL DEFAULT FALSE,
+	expires_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	INDEX uploads_user_uuid_index (user_uuid),
+	INDEX uploads_expires_at_index (expires_at)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE uploads DROP COLUMN is_private;
+ALTER TABLE media DROP COLUMN is_private;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE media ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT FALSE AFTER origin;
+
+ALTER TABLE uploads ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT FALSE AFTER checksum;
@@ -11,16 +11,21 @@ func (s Seed) Permission() {
 	var arr = []string{
 		"role-index", "role-show", "role-store", "role-update", "role-destroy",
 		"permission-index", "permission-show", "permission-store", "permission-update", "permission-destroy",
-		"user-index", "user-show", "user-store", "user-update", "user-destroy",
+		"user-index", "user-show", "user-store", "user-update", "user-destroy", "user-impersonate",
 		"tags-index", "tags-show", "tags-store", "tags-update", "tags-destroy",
-		"post-index", "post-show", "post-store", "post-update", "post-destroy",
+		"category-index", "category-show", "category-store", "category-update", "category-destroy",
+		"media-index", "media-show", "media-store", "media-update", "media-destroy",
+		"post-index", "post-show", "post-store", "post-update", "post-destroy", "post-publish",
+		"comment-index", "comment-show", "comment-update", "comment-destroy", "comment-moderate",
 		"sync-permission-index", "sync-permission-update",
 	}
 	for i := 0; i < len(arr); i++ {
-		_, err := s.db.Exec(`INSERT INTO permissions(uuid, name, created_at) VALUES (?,?,?)`,
+		_, err := s.db.Exec(`INSERT INTO permissions(uuid, name, created_at) SELECT ?,?,? FROM DUAL
+		WHERE NOT EXISTS (SELECT 1 FROM permissions WHERE name = ?)`,
 			uuid.New(),
 			arr[i],
 			time.Now(),
+			arr[i],
 		)
 		if err != nil {
 			panic(err)
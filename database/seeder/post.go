@@ -2,12 +2,14 @@ package seeder
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"math/rand"
 	"strconv"
 
 	model "github.com/arif-x/sqlx-mysql-boilerplate/app/model/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/markup"
 	"github.com/google/uuid"
 
 	"time"
@@ -71,20 +73,46 @@ func (s Seed) PostSeeder() {
 	for i := 0; i < len(users); i++ {
 		for j := 0; j < 3; j++ {
 			ShuffleTag(categories)
-			_, err := s.db.Exec(`INSERT INTO posts(uuid, tag_uuid, user_uuid, title, thumbnail, content, slug, keyword, created_at) VALUES (?,?,?,?,?,?,?,?,?)`,
-				uuid.New(),
-				categories[0].UUID,
+			post_uuid := uuid.New()
+			content := "Post Title " + strconv.Itoa(i+1) + "-" + strconv.Itoa(j+1) + " By " + users[i].Username
+			rendered, err := markup.Render(content, markup.FormatPlain)
+			if err != nil {
+				panic(err)
+			}
+			toc, err := json.Marshal(rendered.TOC)
+			if err != nil {
+				panic(err)
+			}
+			_, err = s.db.Exec(`INSERT INTO posts(uuid, user_uuid, title, thumbnail, content, content_format, content_html, excerpt, reading_time_minutes, toc, slug, keyword, status, published_at, created_at) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+				post_uuid,
 				users[i].UUID,
 				"Title "+strconv.Itoa(i+1)+"-"+strconv.Itoa(j+1),
 				"https://4.bp.blogspot.com/-JU8lLIDYcq4/UkWR38K8pAI/AAAAAAAAQxw/Z-3UaPjKgBw/s1600/images.jpg",
-				"Post Title "+strconv.Itoa(i+1)+"-"+strconv.Itoa(j+1)+" By "+users[i].Username,
+				content,
+				markup.FormatPlain,
+				rendered.HTML,
+				rendered.Excerpt,
+				rendered.ReadingTime,
+				string(toc),
 				"title-"+strconv.Itoa(i+1)+"-"+strconv.Itoa(j+1),
 				"Title 1, Title",
+				model.PostStatusPublished,
+				time.Now(),
 				time.Now(),
 			)
 			if err != nil {
 				panic(err)
 			}
+
+			for k := 0; k <= rand.Intn(len(categories)); k++ {
+				_, err := s.db.Exec(`INSERT INTO post_tags(post_uuid, tag_uuid) VALUES (?,?)`,
+					post_uuid,
+					categories[k].UUID,
+				)
+				if err != nil {
+					panic(err)
+				}
+			}
 		}
 	}
 
@@ -8,15 +8,17 @@ import (
 )
 
 func (s Seed) Role() {
-	var arr = []string{
-		"Superadmin",
-		"Verified",
-		"Inactive",
+	var arr = [][]string{
+		{"Superadmin", "superadmin"},
+		{"Verified", "verified"},
+		{"Inactive", "inactive"},
 	}
 	for i := 0; i < len(arr); i++ {
-		_, err := s.db.Exec(`INSERT INTO roles(uuid, name, created_at) VALUES (?,?,?)`,
+		_, err := s.db.Exec(`INSERT INTO roles(uuid, name, code, is_system, created_at) VALUES (?,?,?,?,?)`,
 			uuid.New(),
-			arr[i],
+			arr[i][0],
+			arr[i][1],
+			true,
 			time.Now(),
 		)
 		if err != nil {
@@ -10,7 +10,7 @@ import (
 )
 
 func (s Seed) RoleHasPermission() {
-	superadmin_q := "SELECT uuid FROM roles WHERE name = 'Superadmin' LIMIT 1"
+	superadmin_q := "SELECT uuid FROM roles WHERE code = 'superadmin' LIMIT 1"
 	var superadmin_role_uuid uuid.UUID
 	_ = s.db.QueryRow(superadmin_q).Scan(&superadmin_role_uuid)
 
@@ -15,11 +15,11 @@ import (
 func (s Seed) UserSeeder() {
 	password, _ := hash.Hash([]byte("password"))
 
-	superadmin_q := "SELECT uuid FROM roles WHERE name = 'Superadmin' LIMIT 1"
+	superadmin_q := "SELECT uuid FROM roles WHERE code = 'superadmin' LIMIT 1"
 	var superadmin_role_uuid uuid.UUID
 	_ = s.db.QueryRow(superadmin_q).Scan(&superadmin_role_uuid)
 
-	inactive_q := "SELECT uuid FROM roles WHERE name = 'Inactive' LIMIT 1"
+	inactive_q := "SELECT uuid FROM roles WHERE code = 'inactive' LIMIT 1"
 	var inactive_role_uuid uuid.UUID
 	_ = s.db.QueryRow(inactive_q).Scan(&inactive_role_uuid)
 
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Numbers of the MySQL errors Translate knows.
+const (
+	erDupEntry        = 1062
+	erDataTooLong     = 1406
+	erLockDeadlock    = 1213
+	erLockWaitTimeout = 1205
+)
+
+var (
+	ErrDuplicate   = errors.New("Duplicate entry")
+	ErrTooLong     = errors.New("Data too long")
+	ErrDeadlock    = errors.New("Deadlock found")
+	ErrLockTimeout = errors.New("Lock wait timeout exceeded")
+)
+
+var (
+	duplicateKey = regexp.MustCompile("for key '([^']+)'")
+	tooLong      = regexp.MustCompile("for column '([^']+)'")
+)
+
+// Error is a MySQL error Translate recognized. Kind is one of the errors above, so errors.Is works on
+// it, and Field the column it is about when the message names one.
+type Error struct {
+	Kind  error
+	Field string
+	Err   *mysql.MySQLError
+}
+
+func (e *Error) Error() string {
+	switch e.Kind {
+	case ErrDuplicate:
+		if e.Field != "" {
+			return fmt.Sprintf("The %s has already been taken", label(e.Field))
+		}
+		return "The resource already exists"
+	case ErrTooLong:
+		if e.Field != "" {
+			return fmt.Sprintf("The %s is too long", label(e.Field))
+		}
+		return "A field is too long"
+	}
+	return "The database is busy, please try again"
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Fields is the message keyed by the field it is about, empty when the field is unknown.
+func (e *Error) Fields() map[string][]string {
+	if e.Field == "" {
+		return nil
+	}
+	return map[string][]string{e.Field: {e.Error()}}
+}
+
+// Duplicate tells whether a unique index was violated.
+func (e *Error) Duplicate() bool {
+	return e.Kind == ErrDuplicate
+}
+
+// Temporary tells whether the same statement may succeed when tried again.
+func (e *Error) Temporary() bool {
+	return e.Kind == ErrDeadlock || e.Kind == ErrLockTimeout
+}
+
+// Translate turns the MySQL errors of duplicate entries, too long data, deadlocks and lock timeouts
+// into an Error. Any other error is returned as is.
+func Translate(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return err
+	}
+
+	switch mysqlErr.Number {
+	case erDupEntry:
+		e := &Error{Kind: ErrDuplicate, Err: mysqlErr}
+		if match := duplicateKey.FindStringSubmatch(mysqlErr.Message); match != nil {
+			e.Field = indexField(match[1])
+		}
+		return e
+	case erDataTooLong:
+		e := &Error{Kind: ErrTooLong, Err: mysqlErr}
+		if match := tooLong.FindStringSubmatch(mysqlErr.Message); match != nil {
+			e.Field = match[1]
+		}
+		return e
+	case erLockDeadlock:
+		return &Error{Kind: ErrDeadlock, Err: mysqlErr}
+	case erLockWaitTimeout:
+		return &Error{Kind: ErrLockTimeout, Err: mysqlErr}
+	}
+
+	return err
+}
+
+// indexField is the field of a unique index. MySQL 8 names the key table.index, indexes declared on
+// a column are named after it and the others table_columns_unique.
+func indexField(key string) string {
+	table := ""
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		table, key = key[:i], key[i+1:]
+	}
+	if key == "PRIMARY" {
+		return ""
+	}
+
+	key = strings.TrimPrefix(key, table+"_")
+	for _, suffix := range []string{"_unique", "_index"} {
+		key = strings.TrimSuffix(key, suffix)
+	}
+	return key
+}
+
+// label is a column name the way messages show it.
+func label(name string) string {
+	return strings.ReplaceAll(name, "_", " ")
+}
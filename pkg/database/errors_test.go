@@ -0,0 +1,117 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		kind      error
+		field     string
+		message   string
+		temporary bool
+	}{
+		{
+			name:    "duplicate on a column index",
+			err:     &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.users_email_unique'"},
+			kind:    ErrDuplicate,
+			field:   "email",
+			message: "The email has already been taken",
+		},
+		{
+			name:    "duplicate on an index named after its column",
+			err:     &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'go' for key 'tags.slug'"},
+			kind:    ErrDuplicate,
+			field:   "slug",
+			message: "The slug has already been taken",
+		},
+		{
+			name:    "duplicate primary key",
+			err:     &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'users.PRIMARY'"},
+			kind:    ErrDuplicate,
+			message: "The resource already exists",
+		},
+		{
+			name:    "duplicate wrapped",
+			err:     fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'x' for key 'posts.posts_slug_unique'"}),
+			kind:    ErrDuplicate,
+			field:   "slug",
+			message: "The slug has already been taken",
+		},
+		{
+			name:    "too long",
+			err:     &mysql.MySQLError{Number: 1406, Message: "Data too long for column 'meta_title' at row 1"},
+			kind:    ErrTooLong,
+			field:   "meta_title",
+			message: "The meta title is too long",
+		},
+		{
+			name:      "deadlock",
+			err:       &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"},
+			kind:      ErrDeadlock,
+			message:   "The database is busy, please try again",
+			temporary: true,
+		},
+		{
+			name:      "lock wait timeout",
+			err:       &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"},
+			kind:      ErrLockTimeout,
+			message:   "The database is busy, please try again",
+			temporary: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Translate(test.err)
+
+			var dbErr *Error
+			if !errors.As(err, &dbErr) {
+				t.Fatalf("Translate(%v) = %T, want *Error", test.err, err)
+			}
+			if !errors.Is(err, test.kind) {
+				t.Errorf("errors.Is(%v, %v) = false", err, test.kind)
+			}
+			var mysqlErr *mysql.MySQLError
+			if !errors.As(err, &mysqlErr) {
+				t.Errorf("the MySQL error is not unwrapped")
+			}
+			if dbErr.Field != test.field {
+				t.Errorf("Field = %q, want %q", dbErr.Field, test.field)
+			}
+			if err.Error() != test.message {
+				t.Errorf("Error() = %q, want %q", err.Error(), test.message)
+			}
+			if dbErr.Duplicate() != (test.kind == ErrDuplicate) {
+				t.Errorf("Duplicate() = %v", dbErr.Duplicate())
+			}
+			if dbErr.Temporary() != test.temporary {
+				t.Errorf("Temporary() = %v, want %v", dbErr.Temporary(), test.temporary)
+			}
+			if fields := dbErr.Fields(); (fields != nil) != (test.field != "") {
+				t.Errorf("Fields() = %v", fields)
+			}
+		})
+	}
+}
+
+func TestTranslateLeavesOtherErrors(t *testing.T) {
+	tests := []error{
+		nil,
+		errors.New("connection refused"),
+		&mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"},
+		&mysql.MySQLError{Number: 1146, Message: "Table 'blog.nope' doesn't exist"},
+	}
+
+	for _, err := range tests {
+		if got := Translate(err); got != err {
+			t.Errorf("Translate(%v) = %v, want it unchanged", err, got)
+		}
+	}
+}
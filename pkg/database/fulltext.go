@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	NaturalLanguageMode = "natural"
+	BooleanMode         = "boolean"
+)
+
+var minTokenSize struct {
+	once sync.Once
+	size int
+}
+
+// MinTokenSize returns the server's innodb_ft_min_token_size. It's read once and falls back
+// to the InnoDB default of 3 when the variable can't be read.
+func (db *DB) MinTokenSize() int {
+	minTokenSize.once.Do(func() {
+		minTokenSize.size = 3
+
+		var name, value string
+		err := db.QueryRowContext(context.Background(), `SHOW VARIABLES LIKE 'innodb_ft_min_token_size'`).Scan(&name, &value)
+		if err != nil {
+			return
+		}
+		if size, err := strconv.Atoi(value); err == nil && size > 0 {
+			minTokenSize.size = size
+		}
+	})
+
+	return minTokenSize.size
+}
+
+// FullText returns a MATCH ... AGAINST expression over columns with a single placeholder for the search,
+// and whether it can be used. Searches without a word of at least MinTokenSize characters can't be
+// matched by the FULLTEXT index, so callers should fall back to Search for them.
+func (db *DB) FullText(columns []string, search string, mode string) (string, bool) {
+	usable := false
+	for _, term := range SearchTerms(search) {
+		if utf8.RuneCountInString(term) >= db.MinTokenSize() {
+			usable = true
+			break
+		}
+	}
+
+	if !usable {
+		return "", false
+	}
+
+	modifier := "IN NATURAL LANGUAGE MODE"
+	if mode == BooleanMode {
+		modifier = "IN BOOLEAN MODE"
+	}
+
+	return fmt.Sprintf("MATCH (%s) AGAINST (? %s)", strings.Join(columns, ", "), modifier), true
+}
+
+// SearchTerms splits a search into its words, dropping boolean mode operators.
+func SearchTerms(search string) []string {
+	return strings.FieldsFunc(search, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
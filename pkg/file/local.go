@@ -0,0 +1,180 @@
+package file
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+)
+
+// LocalStorage keeps files on the local disk under root, served by the app under baseURL. Private
+// files go under privateRoot instead, which is never served as is.
+type LocalStorage struct {
+	root        string
+	privateRoot string
+	baseURL     string
+}
+
+func NewLocalStorage(root string, privateRoot string, baseURL string) *LocalStorage {
+	return &LocalStorage{root: root, privateRoot: privateRoot, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Root is the directory public files are written to.
+func (storage *LocalStorage) Root() string {
+	return storage.root
+}
+
+func (storage *LocalStorage) path(key string) (string, error) {
+	key, err := Key(key)
+	if err != nil {
+		return "", err
+	}
+	if IsPrivate(key) {
+		return filepath.Join(storage.privateRoot, filepath.FromSlash(strings.TrimPrefix(key, PrivatePrefix))), nil
+	}
+	return filepath.Join(storage.root, filepath.FromSlash(key)), nil
+}
+
+func (storage *LocalStorage) Put(key string, r io.Reader, size int64, contentType string) error {
+	name, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		return err
+	}
+
+	dst, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, r); err != nil {
+		return err
+	}
+	return dst.Close()
+}
+
+func (storage *LocalStorage) Get(key string) (io.ReadCloser, error) {
+	name, err := storage.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (storage *LocalStorage) Delete(key string) error {
+	name, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (storage *LocalStorage) Exists(key string) (bool, error) {
+	name, err := storage.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := os.Stat(name); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (storage *LocalStorage) Size(key string) (int64, error) {
+	name, err := storage.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(name)
+	if os.IsNotExist(err) {
+		return 0, ErrNotFound
+	} else if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func (storage *LocalStorage) List(prefix string) ([]Object, error) {
+	objects := []Object{}
+	if err := walk(storage.root, "", prefix, &objects); err != nil {
+		return nil, err
+	}
+	if err := walk(storage.privateRoot, PrivatePrefix, prefix, &objects); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// walk adds the files under dir whose key, the path relative to dir after keyPrefix, starts with
+// prefix.
+func walk(dir string, keyPrefix string, prefix string, objects *[]Object) error {
+	return filepath.WalkDir(dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		key := keyPrefix + filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		*objects = append(*objects, Object{Key: key, Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+}
+
+func (storage *LocalStorage) URL(key string) string {
+	return storage.baseURL + "/" + key
+}
+
+// SignedURL returns the public URL of a public file, which is served statically and never expires.
+// Private files get their URL with an expiry and signature, checked when the app serves them.
+func (storage *LocalStorage) SignedURL(key string, expiry time.Duration) (string, error) {
+	key, err := Key(key)
+	if err != nil {
+		return "", err
+	}
+	if !IsPrivate(key) {
+		return storage.URL(key), nil
+	}
+	if config.StorageCfg().SigningKey == "" {
+		return "", ErrNoSigningKey
+	}
+	return storage.URL(key) + "?" + Sign(key, time.Now().Add(expiry)), nil
+}
@@ -0,0 +1,192 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Storage keeps files in a bucket of any S3-compatible service, such as AWS S3 or MinIO. Private
+// files go to a bucket of their own, so the public bucket can be readable by anyone.
+type S3Storage struct {
+	client        *minio.Client
+	bucket        string
+	privateBucket string
+	publicURL     string
+}
+
+// NewS3Storage connects to the configured endpoint and creates the buckets when they do not exist yet.
+func NewS3Storage(cfg *config.Storage) (*S3Storage, error) {
+	if cfg.S3PrivateBucket == cfg.S3Bucket {
+		return nil, errors.New("S3_PRIVATE_BUCKET must differ from S3_BUCKET")
+	}
+
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure: cfg.S3UseSSL,
+		Region: cfg.S3Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bucket := range []string{cfg.S3Bucket, cfg.S3PrivateBucket} {
+		exists, err := client.BucketExists(context.Background(), bucket)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			if err := client.MakeBucket(context.Background(), bucket, minio.MakeBucketOptions{Region: cfg.S3Region}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	publicURL := strings.TrimRight(cfg.S3PublicURL, "/")
+	if publicURL == "" {
+		publicURL = fmt.Sprintf("%s/%s", strings.TrimRight(client.EndpointURL().String(), "/"), cfg.S3Bucket)
+	}
+
+	return &S3Storage{client: client, bucket: cfg.S3Bucket, privateBucket: cfg.S3PrivateBucket, publicURL: publicURL}, nil
+}
+
+// object returns the bucket and the name a file is kept under. Private files lose their prefix in
+// the private bucket.
+func (storage *S3Storage) object(key string) (string, string, error) {
+	key, err := Key(key)
+	if err != nil {
+		return "", "", err
+	}
+	if IsPrivate(key) {
+		return storage.privateBucket, strings.TrimPrefix(key, PrivatePrefix), nil
+	}
+	return storage.bucket, key, nil
+}
+
+func (storage *S3Storage) Put(key string, r io.Reader, size int64, contentType string) error {
+	bucket, name, err := storage.object(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = storage.client.PutObject(context.Background(), bucket, name, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+func (storage *S3Storage) Get(key string) (io.ReadCloser, error) {
+	bucket, name, err := storage.object(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// GetObject is lazy, so stat first to report missing files up front.
+	if _, err := storage.client.StatObject(context.Background(), bucket, name, minio.StatObjectOptions{}); err != nil {
+		if isS3NotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return storage.client.GetObject(context.Background(), bucket, name, minio.GetObjectOptions{})
+}
+
+func (storage *S3Storage) Delete(key string) error {
+	bucket, name, err := storage.object(key)
+	if err != nil {
+		return err
+	}
+
+	return storage.client.RemoveObject(context.Background(), bucket, name, minio.RemoveObjectOptions{})
+}
+
+func (storage *S3Storage) Exists(key string) (bool, error) {
+	bucket, name, err := storage.object(key)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := storage.client.StatObject(context.Background(), bucket, name, minio.StatObjectOptions{}); err != nil {
+		if isS3NotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (storage *S3Storage) Size(key string) (int64, error) {
+	bucket, name, err := storage.object(key)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := storage.client.StatObject(context.Background(), bucket, name, minio.StatObjectOptions{})
+	if err != nil {
+		if isS3NotFound(err) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	return info.Size, nil
+}
+
+func (storage *S3Storage) List(prefix string) ([]Object, error) {
+	objects := []Object{}
+	if err := storage.list(storage.bucket, "", prefix, &objects); err != nil {
+		return nil, err
+	}
+	if err := storage.list(storage.privateBucket, PrivatePrefix, prefix, &objects); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// list adds the objects of bucket whose key, their name after keyPrefix, starts with prefix.
+func (storage *S3Storage) list(bucket string, keyPrefix string, prefix string, objects *[]Object) error {
+	var name string
+	switch {
+	case strings.HasPrefix(prefix, keyPrefix):
+		name = strings.TrimPrefix(prefix, keyPrefix)
+	case strings.HasPrefix(keyPrefix, prefix):
+		name = ""
+	default:
+		return nil
+	}
+
+	for object := range storage.client.ListObjects(context.Background(), bucket, minio.ListObjectsOptions{Prefix: name, Recursive: true}) {
+		if object.Err != nil {
+			return object.Err
+		}
+		*objects = append(*objects, Object{Key: keyPrefix + object.Key, Size: object.Size, ModTime: object.LastModified})
+	}
+	return nil
+}
+
+func (storage *S3Storage) URL(key string) string {
+	return storage.publicURL + "/" + key
+}
+
+func (storage *S3Storage) SignedURL(key string, expiry time.Duration) (string, error) {
+	bucket, name, err := storage.object(key)
+	if err != nil {
+		return "", err
+	}
+
+	signed, err := storage.client.PresignedGetObject(context.Background(), bucket, name, expiry, nil)
+	if err != nil {
+		return "", err
+	}
+	return signed.String(), nil
+}
+
+func isS3NotFound(err error) bool {
+	code := minio.ToErrorResponse(err).Code
+	return code == "NoSuchKey" || code == "NotFound"
+}
@@ -0,0 +1,106 @@
+package file
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+)
+
+// PrivatePrefix starts the key of every private file. Private files have no public URL, they are
+// only reachable through a signed one.
+const PrivatePrefix = "private/"
+
+var (
+	ErrSignatureInvalid = errors.New("Invalid signature")
+	ErrSignatureExpired = errors.New("Signature has expired")
+	ErrNoSigningKey     = errors.New("No key to sign private file URLs with")
+)
+
+// IsPrivate tells whether a key belongs to a private file.
+func IsPrivate(key string) bool {
+	return strings.HasPrefix(key, PrivatePrefix)
+}
+
+// Link is the URL to hand out for a file: the stable URL of a public file, or a signed URL valid for
+// the configured TTL for a private one.
+func Link(storage Storage, key string) (string, error) {
+	if !IsPrivate(key) {
+		return storage.URL(key), nil
+	}
+	return storage.SignedURL(key, config.StorageCfg().SignedURLTTL)
+}
+
+// LinkURL is Link for a file known by its URL. URLs of files outside the storage are left as is.
+func LinkURL(storage Storage, fileURL string) (string, error) {
+	key, ok := KeyOf(storage, fileURL)
+	if !ok {
+		return fileURL, nil
+	}
+	return Link(storage, key)
+}
+
+// LinkVariants replaces the URLs in encoded variants with their links, see Link.
+func LinkVariants(storage Storage, data []byte) ([]byte, error) {
+	variants := Variants{}
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return nil, err
+	}
+
+	for name, variant := range variants {
+		link, err := LinkURL(storage, variant.URL)
+		if err != nil {
+			return nil, err
+		}
+		variant.URL = link
+		for contentType, alternate := range variant.Alternates {
+			if variant.Alternates[contentType], err = LinkURL(storage, alternate); err != nil {
+				return nil, err
+			}
+		}
+		variants[name] = variant
+	}
+
+	return json.Marshal(variants)
+}
+
+// Sign returns the query that, appended to the URL of key, makes it valid until expires.
+func Sign(key string, expires time.Time) string {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", signature(key, expires.Unix()))
+	return query.Encode()
+}
+
+// Verify checks the expires and signature of a signed URL against the key it points to. Without a
+// signing key anyone could sign, so nothing verifies.
+func Verify(key string, expires string, sig string) error {
+	if config.StorageCfg().SigningKey == "" {
+		return ErrNoSigningKey
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	if !hmac.Equal([]byte(sig), []byte(signature(key, unix))) {
+		return ErrSignatureInvalid
+	}
+	if time.Now().Unix() > unix {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// signature is the HMAC-SHA256 of the key and expiry, so neither can be changed without a new one.
+func signature(key string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(config.StorageCfg().SigningKey))
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,77 @@
+package file
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+)
+
+func withSigningKey(t *testing.T, key string) {
+	previous := config.StorageCfg().SigningKey
+	config.StorageCfg().SigningKey = key
+	t.Cleanup(func() { config.StorageCfg().SigningKey = previous })
+}
+
+func TestSignVerify(t *testing.T) {
+	withSigningKey(t, "secret")
+
+	valid, err := url.ParseQuery(Sign("private/a.pdf", time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := url.ParseQuery(Sign("private/a.pdf", time.Now().Add(-time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		key       string
+		expires   string
+		signature string
+		want      error
+	}{
+		{"valid", "private/a.pdf", valid.Get("expires"), valid.Get("signature"), nil},
+		{"other key", "private/b.pdf", valid.Get("expires"), valid.Get("signature"), ErrSignatureInvalid},
+		{"extended expiry", "private/a.pdf", strconv.FormatInt(time.Now().Add(48*time.Hour).Unix(), 10), valid.Get("signature"), ErrSignatureInvalid},
+		{"malformed expiry", "private/a.pdf", "soon", valid.Get("signature"), ErrSignatureInvalid},
+		{"missing signature", "private/a.pdf", valid.Get("expires"), "", ErrSignatureInvalid},
+		{"expired", "private/a.pdf", expired.Get("expires"), expired.Get("signature"), ErrSignatureExpired},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := Verify(test.key, test.expires, test.signature); err != test.want {
+				t.Errorf("Verify() = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestVerifyWithoutSigningKey(t *testing.T) {
+	withSigningKey(t, "")
+
+	query, err := url.ParseQuery(Sign("private/a.pdf", time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify("private/a.pdf", query.Get("expires"), query.Get("signature")); err != ErrNoSigningKey {
+		t.Errorf("Verify() = %v, want %v", err, ErrNoSigningKey)
+	}
+}
+
+func TestVerifyRejectsOtherSigningKey(t *testing.T) {
+	withSigningKey(t, "old")
+	query, err := url.ParseQuery(Sign("private/a.pdf", time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.StorageCfg().SigningKey = "new"
+	if err := Verify("private/a.pdf", query.Get("expires"), query.Get("signature")); err != ErrSignatureInvalid {
+		t.Errorf("Verify() = %v, want %v", err, ErrSignatureInvalid)
+	}
+}
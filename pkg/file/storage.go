@@ -0,0 +1,91 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrNotFound       = errors.New("File not found")
+	ErrInvalidKey     = errors.New("Invalid file key")
+	ErrUnknownStorage = errors.New("Unknown storage driver")
+	ErrUnknownFormat  = errors.New("Unknown image format")
+)
+
+// Object is a stored file as listed by Storage.List.
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage keeps files under slash-separated keys such as "post/photo_1700000000.jpg".
+type Storage interface {
+	Put(key string, r io.Reader, size int64, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+	Exists(key string) (bool, error)
+	// Size is the size of a file in bytes.
+	Size(key string) (int64, error)
+	// List returns every file whose key starts with prefix.
+	List(prefix string) ([]Object, error)
+	// URL is the stable public link to a file. Private files can't be reached through it.
+	URL(key string) string
+	// SignedURL is a link to a file that stops working after expiry. Use Link to get the right kind
+	// of link for a file.
+	SignedURL(key string, expiry time.Duration) (string, error)
+}
+
+var defaultStorage Storage
+
+// SetUp creates the storage driver selected by the configuration.
+func SetUp(cfg *config.Storage) error {
+	if cfg.ImageExtraFormat != "" && cfg.ImageExtraFormat != FormatWebP {
+		return fmt.Errorf("%w: %s", ErrUnknownFormat, cfg.ImageExtraFormat)
+	}
+
+	var err error
+	switch cfg.Driver {
+	case DriverLocal:
+		defaultStorage = NewLocalStorage(cfg.LocalRoot, cfg.LocalPrivateRoot, strings.TrimRight(cfg.LocalBaseURL, "/")+cfg.LocalURLPrefix)
+	case DriverS3:
+		defaultStorage, err = NewS3Storage(cfg)
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnknownStorage, cfg.Driver)
+	}
+	return err
+}
+
+func GetStorage() Storage {
+	return defaultStorage
+}
+
+// Key joins parts into a storage key, rejecting keys that would escape the storage root.
+func Key(parts ...string) (string, error) {
+	key := path.Clean(strings.TrimLeft(path.Join(parts...), "/"))
+	if key == "." || key == ".." || strings.HasPrefix(key, "../") {
+		return "", ErrInvalidKey
+	}
+	return key, nil
+}
+
+// KeyOf finds the key of a file in storage from its public URL.
+func KeyOf(storage Storage, url string) (string, bool) {
+	prefix := storage.URL("")
+	if !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+	key, err := Key(strings.TrimPrefix(url, prefix))
+	return key, err == nil
+}
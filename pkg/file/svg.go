@@ -0,0 +1,86 @@
+package file
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// svgForbiddenElements can run script or pull other documents into the SVG.
+var svgForbiddenElements = map[string]struct{}{
+	"script":        {},
+	"foreignobject": {},
+	"iframe":        {},
+	"embed":         {},
+	"object":        {},
+	"handler":       {},
+	"listener":      {},
+}
+
+var (
+	errSVGMalformed = errors.New("The SVG is not well-formed XML")
+	errSVGEntities  = errors.New("The SVG must not declare a DOCTYPE or entities")
+	errSVGScript    = errors.New("The SVG must not contain scripts, event handlers or embedded documents")
+	errSVGLink      = errors.New("The SVG must only link to http(s) URLs, images or fragments")
+)
+
+// checkSVG rejects SVGs that could run script when served from our origin: script-like elements,
+// on* event handler attributes, javascript: and other non-http links, and DOCTYPEs, whose entities
+// can expand to anything.
+func checkSVG(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errSVGMalformed
+		}
+
+		switch token := token.(type) {
+		case xml.Directive:
+			return errSVGEntities
+		case xml.ProcInst:
+			if token.Target != "xml" {
+				return errSVGScript
+			}
+		case xml.StartElement:
+			if _, forbidden := svgForbiddenElements[strings.ToLower(token.Name.Local)]; forbidden {
+				return errSVGScript
+			}
+			for _, attr := range token.Attr {
+				name := strings.ToLower(attr.Name.Local)
+				value := strings.ToLower(strings.Join(strings.Fields(attr.Value), ""))
+				if strings.HasPrefix(name, "on") {
+					return errSVGScript
+				}
+				if (name == "href" || name == "src") && !safeSVGLink(value) {
+					return errSVGLink
+				}
+				if strings.Contains(value, "javascript:") || strings.Contains(value, "expression(") {
+					return errSVGScript
+				}
+			}
+		case xml.CharData:
+			text := strings.ToLower(string(token))
+			if strings.Contains(text, "javascript:") || strings.Contains(text, "@import") {
+				return errSVGScript
+			}
+		}
+	}
+}
+
+func safeSVGLink(link string) bool {
+	return link == "" ||
+		strings.HasPrefix(link, "#") ||
+		strings.HasPrefix(link, "http://") ||
+		strings.HasPrefix(link, "https://") ||
+		strings.HasPrefix(link, "data:image/png") ||
+		strings.HasPrefix(link, "data:image/jpeg") ||
+		strings.HasPrefix(link, "data:image/gif")
+}
@@ -0,0 +1,37 @@
+package file
+
+import "testing"
+
+func TestCheckSVG(t *testing.T) {
+	tests := []struct {
+		name string
+		svg  string
+		want error
+	}{
+		{"plain", `<svg xmlns="http://www.w3.org/2000/svg"><circle cx="5" cy="5" r="4"/></svg>`, nil},
+		{"xml declaration", `<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"/>`, nil},
+		{"fragment link", `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><use xlink:href="#a"/></svg>`, nil},
+		{"https image", `<svg xmlns="http://www.w3.org/2000/svg"><image href="https://example.com/a.png"/></svg>`, nil},
+		{"png data image", `<svg xmlns="http://www.w3.org/2000/svg"><image href="data:image/png;base64,AAAA"/></svg>`, nil},
+		{"malformed", `<svg><g></svg>`, errSVGMalformed},
+		{"doctype", `<!DOCTYPE svg [<!ENTITY a "b">]><svg>&a;</svg>`, errSVGEntities},
+		{"script", `<svg><script>alert(1)</script></svg>`, errSVGScript},
+		{"script upper case", `<svg><SCRIPT>alert(1)</SCRIPT></svg>`, errSVGScript},
+		{"foreign object", `<svg><foreignObject><p>x</p></foreignObject></svg>`, errSVGScript},
+		{"event handler", `<svg onload="alert(1)"/>`, errSVGScript},
+		{"processing instruction", `<?xml-stylesheet href="a.xsl"?><svg/>`, errSVGScript},
+		{"javascript link", `<svg><a href="javascript:alert(1)"><text>x</text></a></svg>`, errSVGLink},
+		{"javascript link split by spaces", `<svg><a href="java script:alert(1)"><text>x</text></a></svg>`, errSVGLink},
+		{"svg data link", `<svg><image href="data:image/svg+xml;base64,AAAA"/></svg>`, errSVGLink},
+		{"javascript in style", `<svg><rect style="background:url(javascript:alert(1))"/></svg>`, errSVGScript},
+		{"style import", `<svg><style>@import url(https://example.com/a.css);</style></svg>`, errSVGScript},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := checkSVG([]byte(test.svg)); err != test.want {
+				t.Errorf("checkSVG() = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
@@ -2,12 +2,12 @@ package file
 
 import (
 	"fmt"
-	"io"
-	"mime/multipart"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
 )
 
 func GenerateUniqueFilename(filename string) string {
@@ -20,23 +20,16 @@ func GetTimestamp() int64 {
 	return time.Now().Unix()
 }
 
-func SaveFile(file *multipart.FileHeader, uploadDir, filename string) error {
-	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-		return err
-	}
-
-	src, err := file.Open()
-	if err != nil {
-		return err
-	}
-	defer src.Close()
+// TempPath is where the received part of a resumable upload is kept until it is complete. Every
+// chunk of an upload is appended there, so all instances must see the same UPLOAD_TEMP_DIR.
+func TempPath(UUID string) string {
+	return filepath.Join(config.StorageCfg().UploadTempDir, UUID)
+}
 
-	dst, err := os.Create(filepath.Join(uploadDir, filename))
-	if err != nil {
+// RemoveTemp deletes the received part of a resumable upload, if there is any.
+func RemoveTemp(UUID string) error {
+	if err := os.Remove(TempPath(UUID)); err != nil && !os.IsNotExist(err) {
 		return err
 	}
-	defer dst.Close()
-
-	_, err = io.Copy(dst, src)
-	return err
+	return nil
 }
@@ -0,0 +1,237 @@
+package file
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+)
+
+const (
+	MIMEJPEG = "image/jpeg"
+	MIMEPNG  = "image/png"
+	MIMEGIF  = "image/gif"
+	MIMESVG  = "image/svg+xml"
+)
+
+// Extensions maps the content types uploads are detected as to the extension they are stored with.
+var Extensions = map[string]string{
+	MIMEJPEG: ".jpg",
+	MIMEPNG:  ".png",
+	MIMEGIF:  ".gif",
+	MIMESVG:  ".svg",
+}
+
+// Rule limits what an upload field accepts. A zero MaxWidth or MaxHeight leaves that side unbounded.
+type Rule struct {
+	MaxSize   int64
+	MaxWidth  int
+	MaxHeight int
+	Types     []string
+}
+
+// ThumbnailRule is the rule for post thumbnails.
+func ThumbnailRule() Rule {
+	cfg := config.StorageCfg()
+	return Rule{
+		MaxSize:   cfg.ThumbnailMaxSize,
+		MaxWidth:  cfg.ThumbnailMaxWidth,
+		MaxHeight: cfg.ThumbnailMaxHeight,
+		Types:     []string{MIMEJPEG, MIMEPNG, MIMEGIF, MIMESVG},
+	}
+}
+
+// MediaRule is the rule for files uploaded to the media library, held to the thumbnail limits.
+func MediaRule() Rule {
+	return ThumbnailRule()
+}
+
+// ResumableRule is the rule for files sent through resumable uploads, which may be far larger than
+// a single request allows.
+func ResumableRule() Rule {
+	cfg := config.StorageCfg()
+	return Rule{
+		MaxSize:   cfg.ResumableMaxSize,
+		MaxWidth:  cfg.ResumableMaxWidth,
+		MaxHeight: cfg.ResumableMaxHeight,
+		Types:     []string{MIMEJPEG, MIMEPNG, MIMEGIF, MIMESVG},
+	}
+}
+
+// UploadError is a rejected upload, carrying the field it was sent in and the HTTP status to answer with.
+type UploadError struct {
+	Field   string
+	Status  int
+	Message string
+}
+
+func (err *UploadError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Field, err.Message)
+}
+
+// Upload is a validated file, re-encoded so that it carries no metadata.
+type Upload struct {
+	Data        []byte
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// Validate checks an uploaded file against rule by its content rather than its name: the detected
+// type must be allowed, the size and pixel dimensions within bounds, raster images are re-encoded to
+// drop EXIF and other metadata, and SVGs with scripts, event handlers or entities are rejected.
+func Validate(field string, header *multipart.FileHeader, rule Rule) (*Upload, error) {
+	src, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	return ValidateReader(field, src, header.Size, rule)
+}
+
+// ValidateReader is Validate for a file read from src, of the given size.
+func ValidateReader(field string, src io.Reader, size int64, rule Rule) (*Upload, error) {
+	if rule.MaxSize > 0 && size > rule.MaxSize {
+		return nil, TooLarge(field, rule.MaxSize)
+	}
+
+	var reader io.Reader = src
+	if rule.MaxSize > 0 {
+		reader = io.LimitReader(src, rule.MaxSize+1)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if rule.MaxSize > 0 && int64(len(data)) > rule.MaxSize {
+		return nil, TooLarge(field, rule.MaxSize)
+	}
+	if len(data) == 0 {
+		return nil, &UploadError{Field: field, Status: http.StatusBadRequest, Message: "The file is empty"}
+	}
+
+	contentType := DetectContentType(data)
+	if !allowed(rule.Types, contentType) {
+		return nil, &UploadError{Field: field, Status: http.StatusUnsupportedMediaType, Message: fmt.Sprintf("Files of type %s are not allowed", contentType)}
+	}
+
+	if contentType == MIMESVG {
+		if err := checkSVG(data); err != nil {
+			return nil, &UploadError{Field: field, Status: http.StatusBadRequest, Message: err.Error()}
+		}
+		return &Upload{Data: data, ContentType: contentType, Ext: Extensions[contentType]}, nil
+	}
+
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, &UploadError{Field: field, Status: http.StatusBadRequest, Message: "The image can't be decoded"}
+	}
+	if (rule.MaxWidth > 0 && imageConfig.Width > rule.MaxWidth) || (rule.MaxHeight > 0 && imageConfig.Height > rule.MaxHeight) {
+		return nil, &UploadError{Field: field, Status: http.StatusBadRequest, Message: fmt.Sprintf("The image is %dx%d pixels, at most %dx%d are allowed", imageConfig.Width, imageConfig.Height, rule.MaxWidth, rule.MaxHeight)}
+	}
+
+	upload, err := reencode(data, contentType)
+	if err != nil {
+		return nil, &UploadError{Field: field, Status: http.StatusBadRequest, Message: "The image can't be decoded"}
+	}
+	return upload, nil
+}
+
+// DetectContentType sniffs the content type of data, telling SVG apart from other XML and text.
+func DetectContentType(data []byte) string {
+	contentType := http.DetectContentType(data)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	if (contentType == "text/xml" || contentType == "text/plain") && isSVG(data) {
+		return MIMESVG
+	}
+	return contentType
+}
+
+func allowed(types []string, contentType string) bool {
+	for _, t := range types {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// TooLarge is the error for a file larger than max bytes.
+func TooLarge(field string, max int64) error {
+	return &UploadError{Field: field, Status: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("The file is larger than %d KB", max/1024)}
+}
+
+// reencode decodes and encodes the image again in its own format, which keeps the pixels and drops
+// everything else. JPEGs are turned upright first, as the EXIF orientation goes with the metadata.
+func reencode(data []byte, contentType string) (*Upload, error) {
+	var out bytes.Buffer
+	var bounds image.Rectangle
+
+	switch contentType {
+	case MIMEJPEG:
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		img = orient(img, jpegOrientation(data))
+		bounds = img.Bounds()
+		if err := jpeg.Encode(&out, img, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, err
+		}
+	case MIMEPNG:
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		bounds = img.Bounds()
+		if err := png.Encode(&out, img); err != nil {
+			return nil, err
+		}
+	case MIMEGIF:
+		img, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		bounds = image.Rect(0, 0, img.Config.Width, img.Config.Height)
+		if err := gif.EncodeAll(&out, img); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("can't re-encode %s", contentType)
+	}
+
+	return &Upload{
+		Data:        out.Bytes(),
+		ContentType: contentType,
+		Ext:         Extensions[contentType],
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+	}, nil
+}
+
+// isSVG reports whether the first element of data is an svg element.
+func isSVG(data []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return strings.EqualFold(start.Name.Local, "svg")
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+	"golang.org/x/image/draw"
+)
+
+const (
+	FormatWebP = "webp"
+	MIMEWebP   = "image/webp"
+)
+
+// Variant is a resized copy of an image. Alternates has the same copy in other formats, keyed by
+// content type, for <picture> sources.
+type Variant struct {
+	URL         string            `json:"url"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	ContentType string            `json:"type"`
+	Alternates  map[string]string `json:"alternates,omitempty"`
+}
+
+// Variants are the variants of one image by name.
+type Variants map[string]Variant
+
+// MakeVariants resizes the image stored under key into every configured variant and stores them
+// next to it, as key suffixed with the variant name. Variants are never upscaled. SVGs scale by
+// themselves and get none.
+func MakeVariants(storage Storage, key string, data []byte, contentType string) (Variants, error) {
+	variants := Variants{}
+	if contentType == MIMESVG {
+		return variants, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// GIF variants lose their animation anyway, so they are stored as PNG.
+	if contentType == MIMEGIF {
+		contentType = MIMEPNG
+	}
+
+	cfg := config.StorageCfg()
+	ext := path.Ext(key)
+	base := strings.TrimSuffix(key, ext)
+
+	for _, variant := range cfg.ImageVariants {
+		resized := resize(img, variant)
+
+		var out bytes.Buffer
+		if contentType == MIMEJPEG {
+			err = jpeg.Encode(&out, resized, &jpeg.Options{Quality: 85})
+		} else {
+			err = png.Encode(&out, resized)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		variantKey := base + "_" + variant.Name + Extensions[contentType]
+		if err := storage.Put(variantKey, &out, int64(out.Len()), contentType); err != nil {
+			return nil, err
+		}
+
+		bounds := resized.Bounds()
+		made := Variant{
+			URL:         storage.URL(variantKey),
+			Width:       bounds.Dx(),
+			Height:      bounds.Dy(),
+			ContentType: contentType,
+		}
+
+		if cfg.ImageExtraFormat == FormatWebP {
+			out.Reset()
+			if err := nativewebp.Encode(&out, resized, nil); err != nil {
+				return nil, err
+			}
+			webpKey := base + "_" + variant.Name + ".webp"
+			if err := storage.Put(webpKey, &out, int64(out.Len()), MIMEWebP); err != nil {
+				return nil, err
+			}
+			made.Alternates = map[string]string{MIMEWebP: storage.URL(webpKey)}
+		}
+
+		variants[variant.Name] = made
+	}
+
+	return variants, nil
+}
+
+// DeleteVariants removes the stored files of variants, skipping URLs that are not in storage.
+func DeleteVariants(storage Storage, variants Variants) error {
+	for _, variant := range variants {
+		urls := []string{variant.URL}
+		for _, url := range variant.Alternates {
+			urls = append(urls, url)
+		}
+		for _, url := range urls {
+			if key, ok := KeyOf(storage, url); ok {
+				if err := storage.Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// RegenerateVariants makes the variants of the image stored under key again from the current
+// configuration, deleting the old ones given as JSON first.
+func RegenerateVariants(storage Storage, key string, old []byte) (Variants, error) {
+	src, err := storage.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(src)
+	src.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(old) > 0 {
+		variants := Variants{}
+		if err := json.Unmarshal(old, &variants); err == nil {
+			if err := DeleteVariants(storage, variants); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return MakeVariants(storage, key, data, DetectContentType(data))
+}
+
+// DeleteWithVariants removes the file stored under key along with its variants, given as JSON.
+func DeleteWithVariants(storage Storage, key string, variants []byte) error {
+	if len(variants) > 0 {
+		made := Variants{}
+		if err := json.Unmarshal(variants, &made); err != nil {
+			return err
+		}
+		if err := DeleteVariants(storage, made); err != nil {
+			return err
+		}
+	}
+	return storage.Delete(key)
+}
+
+// resize scales img down to the variant's width, cropping around the centre when the variant has a
+// height as well.
+func resize(img image.Image, variant config.ImageVariant) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	src := bounds
+	tw, th := variant.Width, variant.Height
+	if th == 0 {
+		if w <= tw {
+			return img
+		}
+		th = max(1, h*tw/w)
+	} else {
+		cw, ch := w, max(1, w*th/tw)
+		if ch > h {
+			cw, ch = max(1, h*tw/th), h
+		}
+		x0 := bounds.Min.X + (w-cw)/2
+		y0 := bounds.Min.Y + (h-ch)/2
+		src = image.Rect(x0, y0, x0+cw, y0+ch)
+		if cw < tw {
+			tw, th = cw, ch
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,50 @@
+package highlight
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Snippet cuts a window of about radius characters on each side of the first term found in text,
+// HTML-escapes it and wraps every term in <mark> tags. Without a match the start of text is used.
+func Snippet(text string, terms []string, radius int) string {
+	raw, escaped := []string{}, []string{}
+	for _, term := range terms {
+		if term != "" {
+			raw = append(raw, regexp.QuoteMeta(term))
+			escaped = append(escaped, regexp.QuoteMeta(html.EscapeString(term)))
+		}
+	}
+
+	start := 0
+	if len(raw) > 0 {
+		if loc := regexp.MustCompile("(?i)" + strings.Join(raw, "|")).FindStringIndex(text); loc != nil {
+			start = utf8.RuneCountInString(text[:loc[0]]) - radius
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	runes := []rune(text)
+	end := start + radius*2
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	snippet := html.EscapeString(string(runes[start:end]))
+	if len(escaped) > 0 {
+		snippet = regexp.MustCompile("(?i)"+strings.Join(escaped, "|")).ReplaceAllString(snippet, "<mark>$0</mark>")
+	}
+
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+
+	return snippet
+}
@@ -11,10 +11,10 @@ type Logger struct {
 	*logrus.Logger
 }
 
-var logger = &Logger{}
+var logger = &Logger{logrus.New()}
 
+// SetUpLogger configures the logger in place, so loggers taken before keep working.
 func SetUpLogger() {
-	logger = &Logger{logrus.New()}
 	logger.Formatter = &logrus.JSONFormatter{}
 	logger.SetOutput(os.Stdout)
 
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// LocalsKey is where the logger of a request is kept in its locals.
+const LocalsKey = "logger"
+
+type contextKey struct{}
+
+// SetRequest keeps entry as the logger of the request, in its locals and user context, so handlers
+// and what they call with c.UserContext() log with the fields of the request.
+func SetRequest(c *fiber.Ctx, entry *logrus.Entry) {
+	c.Locals(LocalsKey, entry)
+	c.SetUserContext(context.WithValue(c.UserContext(), contextKey{}, entry))
+}
+
+// AddField adds a field, such as the authenticated user, to the logger of the request.
+func AddField(c *fiber.Ctx, key string, value interface{}) {
+	SetRequest(c, Request(c).WithField(key, value))
+}
+
+// Request returns the logger of the request with the route it matched, the default logger outside
+// of one.
+func Request(c *fiber.Ctx) *logrus.Entry {
+	entry, ok := c.Locals(LocalsKey).(*logrus.Entry)
+	if !ok {
+		entry = logrus.NewEntry(GetLogger().Logger)
+	}
+	if route := c.Route(); route != nil && route.Path != "/" {
+		entry = entry.WithField("route", route.Path)
+	}
+	return entry
+}
+
+// FromContext returns the logger of the request ctx belongs to, the default logger otherwise.
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(GetLogger().Logger)
+}
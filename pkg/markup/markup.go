@@ -0,0 +1,212 @@
+package markup
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gosimple/slug"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	nethtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Content formats a post can be written in.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatPlain    = "plain"
+)
+
+// ExcerptLength is the most characters an excerpt keeps before it's cut at a word boundary.
+const ExcerptLength = 200
+
+// WordsPerMinute is the reading speed used for reading time.
+const WordsPerMinute = 200
+
+var ErrInvalidFormat = errors.New("content_format must be markdown, html or plain")
+
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+type Rendered struct {
+	HTML        string
+	Excerpt     string
+	ReadingTime int
+	TOC         []Heading
+}
+
+// Raw HTML is let through goldmark on purpose, everything it renders goes through the sanitizer.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+)
+
+var headingID = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(headingID).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	return p
+}()
+
+// Render turns source written in format into sanitized HTML, giving every heading an id, and derives
+// the excerpt, reading time and table of contents from the result.
+func Render(source string, format string) (Rendered, error) {
+	var unsafe string
+	switch format {
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(source), &buf); err != nil {
+			return Rendered{}, err
+		}
+		unsafe = buf.String()
+	case FormatHTML:
+		unsafe = source
+	case FormatPlain:
+		unsafe = plain(source)
+	default:
+		return Rendered{}, ErrInvalidFormat
+	}
+
+	body := &nethtml.Node{Type: nethtml.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := nethtml.ParseFragment(strings.NewReader(policy.Sanitize(unsafe)), body)
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	rendered := Rendered{TOC: []Heading{}}
+	ids := map[string]int{}
+	words := []string{}
+
+	var walk func(node *nethtml.Node)
+	walk = func(node *nethtml.Node) {
+		if node.Type == nethtml.TextNode {
+			words = append(words, strings.Fields(node.Data)...)
+			return
+		}
+		if level := headingLevel(node); level > 0 {
+			text := strings.Join(strings.Fields(textOf(node)), " ")
+			id := uniqueID(headingAttr(node), text, ids)
+			setAttr(node, "id", id)
+			rendered.TOC = append(rendered.TOC, Heading{Level: level, ID: id, Text: text})
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, node := range nodes {
+		walk(node)
+		if err := nethtml.Render(&buf, node); err != nil {
+			return Rendered{}, err
+		}
+	}
+
+	rendered.HTML = buf.String()
+	rendered.Excerpt = excerpt(words)
+	rendered.ReadingTime = int(math.Ceil(float64(len(words)) / WordsPerMinute))
+
+	return rendered, nil
+}
+
+// plain escapes text and keeps its paragraphs and line breaks.
+func plain(text string) string {
+	paragraphs := regexp.MustCompile(`\r?\n\s*\r?\n`).Split(strings.TrimSpace(text), -1)
+
+	var buf strings.Builder
+	for _, paragraph := range paragraphs {
+		if paragraph == "" {
+			continue
+		}
+		lines := strings.Split(strings.TrimSpace(paragraph), "\n")
+		for i, line := range lines {
+			lines[i] = html.EscapeString(strings.TrimRight(line, "\r"))
+		}
+		buf.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	}
+	return buf.String()
+}
+
+func excerpt(words []string) string {
+	text := strings.Join(words, " ")
+	if utf8.RuneCountInString(text) <= ExcerptLength {
+		return text
+	}
+
+	cut := string([]rune(text)[:ExcerptLength])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, ".,;:!?-") + "…"
+}
+
+func headingLevel(node *nethtml.Node) int {
+	if node.Type != nethtml.ElementNode {
+		return 0
+	}
+	switch node.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return int(node.Data[1] - '0')
+	}
+	return 0
+}
+
+func textOf(node *nethtml.Node) string {
+	if node.Type == nethtml.TextNode {
+		return node.Data
+	}
+	var text strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		text.WriteString(textOf(child) + " ")
+	}
+	return text.String()
+}
+
+func headingAttr(node *nethtml.Node) string {
+	for _, attr := range node.Attr {
+		if attr.Key == "id" {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(node *nethtml.Node, key string, val string) {
+	for i, attr := range node.Attr {
+		if attr.Key == key {
+			node.Attr[i].Val = val
+			return
+		}
+	}
+	node.Attr = append(node.Attr, nethtml.Attribute{Key: key, Val: val})
+}
+
+// uniqueID keeps a well formed id the author gave the heading, otherwise slugs its text, numbering repeats.
+func uniqueID(id string, text string, seen map[string]int) string {
+	if !headingID.MatchString(id) {
+		id = slug.Make(text)
+	}
+	if id == "" {
+		id = "section"
+	}
+
+	seen[id]++
+	if seen[id] > 1 {
+		return id + "-" + strconv.Itoa(seen[id])
+	}
+	return id
+}
@@ -0,0 +1,183 @@
+package markup
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRenderTOC(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		format string
+		toc    []Heading
+	}{
+		{
+			name:   "markdown headings",
+			source: "# Getting Started\n\ntext\n\n## Install the CLI\n",
+			format: FormatMarkdown,
+			toc:    []Heading{{1, "getting-started", "Getting Started"}, {2, "install-the-cli", "Install the CLI"}},
+		},
+		{
+			name:   "repeated headings",
+			source: "## Usage\n\n## Usage\n\n## Usage\n",
+			format: FormatMarkdown,
+			toc:    []Heading{{2, "usage", "Usage"}, {2, "usage-2", "Usage"}, {2, "usage-3", "Usage"}},
+		},
+		{
+			name:   "author id kept",
+			source: `<h2 id="intro">Introduction</h2>`,
+			format: FormatHTML,
+			toc:    []Heading{{2, "intro", "Introduction"}},
+		},
+		{
+			name:   "malformed author id replaced",
+			source: `<h2 id="Not An Id">Introduction</h2>`,
+			format: FormatHTML,
+			toc:    []Heading{{2, "introduction", "Introduction"}},
+		},
+		{
+			name:   "heading without text",
+			source: `<h3><img src="https://example.com/a.png"></h3>`,
+			format: FormatHTML,
+			toc:    []Heading{{3, "section", ""}},
+		},
+		{
+			name:   "nested markup in heading",
+			source: "## Using `go test`\n",
+			format: FormatMarkdown,
+			toc:    []Heading{{2, "using-go-test", "Using go test"}},
+		},
+		{
+			name:   "plain has no headings",
+			source: "# not a heading",
+			format: FormatPlain,
+			toc:    []Heading{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rendered, err := Render(test.source, test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rendered.TOC, test.toc) {
+				t.Errorf("TOC = %+v, want %+v", rendered.TOC, test.toc)
+			}
+			for _, heading := range test.toc {
+				if !strings.Contains(rendered.HTML, `id="`+heading.ID+`"`) {
+					t.Errorf("HTML %q has no heading with id %q", rendered.HTML, heading.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		format   string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "script in html",
+			source:   `<p>hi</p><script>alert(1)</script>`,
+			format:   FormatHTML,
+			contains: []string{"<p>hi</p>"},
+			excludes: []string{"<script", "alert(1)"},
+		},
+		{
+			name:     "raw html in markdown",
+			source:   "hello <img src=x onerror=alert(1)>\n",
+			format:   FormatMarkdown,
+			excludes: []string{"onerror"},
+		},
+		{
+			name:     "javascript link",
+			source:   "[click](javascript:alert(1))\n",
+			format:   FormatMarkdown,
+			excludes: []string{"javascript:"},
+		},
+		{
+			name:     "code language kept",
+			source:   "```go\nfmt.Println()\n```\n",
+			format:   FormatMarkdown,
+			contains: []string{`class="language-go"`},
+		},
+		{
+			name:     "plain escaped",
+			source:   "<b>bold</b>\nnext line\n\nsecond paragraph",
+			format:   FormatPlain,
+			contains: []string{"<p>&lt;b&gt;bold&lt;/b&gt;<br/>", "<p>second paragraph</p>"},
+			excludes: []string{"<b>"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rendered, err := Render(test.source, test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range test.contains {
+				if !strings.Contains(rendered.HTML, want) {
+					t.Errorf("HTML %q does not contain %q", rendered.HTML, want)
+				}
+			}
+			for _, unwanted := range test.excludes {
+				if strings.Contains(rendered.HTML, unwanted) {
+					t.Errorf("HTML %q contains %q", rendered.HTML, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderExcerpt(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+
+	tests := []struct {
+		name        string
+		source      string
+		format      string
+		excerpt     string
+		readingTime int
+	}{
+		{"empty", "", FormatPlain, "", 0},
+		{"short", "# Title\n\nSome *text* here.\n", FormatMarkdown, "Title Some text here.", 1},
+		{"tags stripped", `<p>one<br>two</p>`, FormatHTML, "one two", 1},
+		{"cut at a word", long, FormatPlain, strings.TrimSpace(strings.Repeat("word ", 40)) + "…", 1},
+		{"reading time rounds up", strings.Repeat("word ", 201), FormatPlain, "", 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rendered, err := Render(test.source, test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.excerpt != "" || test.source == "" {
+				if rendered.Excerpt != test.excerpt {
+					t.Errorf("Excerpt = %q, want %q", rendered.Excerpt, test.excerpt)
+				}
+			}
+			if utf8.RuneCountInString(rendered.Excerpt) > ExcerptLength+1 {
+				t.Errorf("Excerpt is %d characters long", utf8.RuneCountInString(rendered.Excerpt))
+			}
+			if rendered.ReadingTime != test.readingTime {
+				t.Errorf("ReadingTime = %d, want %d", rendered.ReadingTime, test.readingTime)
+			}
+		})
+	}
+}
+
+func TestRenderInvalidFormat(t *testing.T) {
+	if _, err := Render("text", "rtf"); err != ErrInvalidFormat {
+		t.Errorf("Render() = %v, want %v", err, ErrInvalidFormat)
+	}
+}
@@ -7,8 +7,6 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-var logr = logger.GetLogger()
-
 func Paginate(c *fiber.Ctx) (pageNo int, pageSize int, search string, sortBy string, sort string) {
 	ps := c.Query("limit")
 	pn := c.Query("page")
@@ -28,7 +26,7 @@ func Paginate(c *fiber.Ctx) (pageNo int, pageSize int, search string, sortBy str
 	if len(ps) > 0 {
 		psInt, err := strconv.Atoi(ps)
 		if err != nil {
-			logr.Error(err)
+			logger.Request(c).Error(err)
 		} else {
 			pageSize = psInt
 		}
@@ -37,7 +35,7 @@ func Paginate(c *fiber.Ctx) (pageNo int, pageSize int, search string, sortBy str
 	if len(pn) > 0 {
 		pnInt, err := strconv.Atoi(pn)
 		if err != nil {
-			logr.Error(err)
+			logger.Request(c).Error(err)
 		} else {
 			pageNo = pnInt
 		}
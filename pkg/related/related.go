@@ -0,0 +1,69 @@
+package related
+
+import "sync"
+
+// call is a build of the entry of a key that other lookups of the key wait for.
+type call struct {
+	done chan struct{}
+	data interface{}
+	err  error
+}
+
+var cache struct {
+	sync.Mutex
+	entries    map[string]interface{}
+	calls      map[string]*call
+	generation int
+}
+
+// Cached returns the related posts cached for a post under key, building them with build on a miss.
+// The lock isn't held while building, and concurrent misses of the same key share one build.
+func Cached(key string, build func() (interface{}, error)) (interface{}, error) {
+	cache.Lock()
+	if data, ok := cache.entries[key]; ok {
+		cache.Unlock()
+		return data, nil
+	}
+	if pending, ok := cache.calls[key]; ok {
+		cache.Unlock()
+		<-pending.done
+		return pending.data, pending.err
+	}
+
+	pending := &call{done: make(chan struct{})}
+	if cache.calls == nil {
+		cache.calls = map[string]*call{}
+	}
+	cache.calls[key] = pending
+	generation := cache.generation
+	cache.Unlock()
+
+	pending.data, pending.err = build()
+
+	cache.Lock()
+	delete(cache.calls, key)
+	// Don't keep what was built from data an Invalidate during the build made stale.
+	if pending.err == nil && generation == cache.generation {
+		if cache.entries == nil {
+			cache.entries = map[string]interface{}{}
+		}
+		cache.entries[key] = pending.data
+	}
+	cache.Unlock()
+	close(pending.done)
+
+	if pending.err != nil {
+		return nil, pending.err
+	}
+	return pending.data, nil
+}
+
+// Invalidate drops every cached entry. Changing one post or tag can move it in or out of any other
+// post's related list, and the entries carry comment counts, so call it whenever posts, tags or
+// comments change.
+func Invalidate() {
+	cache.Lock()
+	cache.entries = nil
+	cache.generation++
+	cache.Unlock()
+}
@@ -0,0 +1,288 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Codes of the errors clients can branch on. They stay the same when messages change.
+const (
+	CodeBadRequest         = "BAD_REQUEST"
+	CodeValidationFailed   = "VALIDATION_FAILED"
+	CodeUnauthenticated    = "AUTH_UNAUTHENTICATED"
+	CodeTokenMissing       = "AUTH_TOKEN_MISSING"
+	CodeTokenInvalid       = "AUTH_TOKEN_INVALID"
+	CodeTokenExpired       = "AUTH_TOKEN_EXPIRED"
+	CodeInvalidCredentials = "AUTH_INVALID_CREDENTIALS"
+	CodeEmailNotVerified   = "AUTH_EMAIL_NOT_VERIFIED"
+	CodeEmailVerified      = "AUTH_EMAIL_ALREADY_VERIFIED"
+	CodeInvalidCode        = "AUTH_INVALID_VERIFICATION_CODE"
+	CodeUserInactive       = "AUTH_USER_INACTIVE"
+	CodePermissionDenied   = "PERMISSION_DENIED"
+	CodeNotFound           = "RESOURCE_NOT_FOUND"
+	CodeRouteNotFound      = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed   = "METHOD_NOT_ALLOWED"
+	CodeConflict           = "RESOURCE_CONFLICT"
+	CodeDuplicate          = "RESOURCE_DUPLICATE"
+	CodeGone               = "RESOURCE_GONE"
+	CodePreconditionFailed = "PRECONDITION_FAILED"
+	CodeUploadRejected     = "UPLOAD_REJECTED"
+	CodeUploadTooLarge     = "UPLOAD_TOO_LARGE"
+	CodeUploadType         = "UPLOAD_UNSUPPORTED_TYPE"
+	CodeUploadChecksum     = "UPLOAD_CHECKSUM_MISMATCH"
+	CodeEmailNotSent       = "EMAIL_NOT_SENT"
+	CodeRequestTooLarge    = "REQUEST_TOO_LARGE"
+	CodeTimeout            = "REQUEST_TIMEOUT"
+	CodeTooManyRequests    = "TOO_MANY_REQUESTS"
+	CodeUnavailable        = "SERVICE_UNAVAILABLE"
+	CodeInternal           = "INTERNAL_ERROR"
+)
+
+const (
+	// FormatEnvelope sends errors in the same status/message/data envelope as every other response.
+	FormatEnvelope = "envelope"
+	// FormatProblem sends errors as RFC 7807 application/problem+json.
+	FormatProblem = "problem"
+
+	MIMEProblemJSON = "application/problem+json"
+
+	// LocalsKey is where Fail keeps the error it sent, for the access log.
+	LocalsKey = "error"
+)
+
+// Error is what every failed request answers with: an HTTP status, a stable code, a message for
+// people, optional details such as the failed fields, and the ID of the request to find it in logs.
+type Error struct {
+	Status    int
+	Code      string
+	Message   string
+	Details   interface{}
+	RequestID string
+	// Err is the cause, logged but never sent.
+	Err error
+}
+
+func NewError(status int, code string, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Err.Error()
+	}
+	return e.Code + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithDetails returns a copy of the error with details.
+func (e *Error) WithDetails(details interface{}) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// WithCause returns a copy of the error caused by err.
+func (e *Error) WithCause(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+// fieldErrors are errors that know which request fields they are about, such as failed validation.
+type fieldErrors interface {
+	error
+	Fields() map[string][]string
+}
+
+// Errors of the database, see Translate.
+type (
+	duplicate interface {
+		error
+		Duplicate() bool
+	}
+	temporary interface {
+		error
+		Temporary() bool
+	}
+)
+
+var (
+	debug       bool
+	format      = FormatEnvelope
+	translators []func(error) error
+)
+
+// SetUp sets whether internal errors are sent with their cause and the default error format,
+// FormatEnvelope or FormatProblem.
+func SetUp(debugMode bool, errorFormat string) {
+	debug = debugMode
+	format = FormatEnvelope
+	if errorFormat == FormatProblem {
+		format = FormatProblem
+	}
+}
+
+// Translate adds fn to the functions From passes errors through first, to turn errors of other
+// layers, such as the database driver, into ones it knows.
+func Translate(fn func(error) error) {
+	translators = append(translators, fn)
+}
+
+// ErrorHandler is the Fiber error handler: whatever error a handler or middleware returns is sent as
+// an Error.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	return Fail(c, From(err))
+}
+
+// From turns any error into an Error. Duplicates are conflicts, temporary
+// errors such as deadlocks 503 and errors about fields 422. The others are internal, apart from
+// those Fiber itself returns, which keep their status.
+func From(err error) *Error {
+	for _, translate := range translators {
+		err = translate(err)
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	var dup duplicate
+	if errors.As(err, &dup) && dup.Duplicate() {
+		return NewError(fiber.StatusConflict, CodeDuplicate, dup.Error()).WithDetails(fields(err)).WithCause(err)
+	}
+
+	var temp temporary
+	if errors.As(err, &temp) && temp.Temporary() {
+		return NewError(fiber.StatusServiceUnavailable, CodeUnavailable, temp.Error()).WithCause(err)
+	}
+
+	var fe fieldErrors
+	if errors.As(err, &fe) {
+		return NewError(fiber.StatusUnprocessableEntity, CodeValidationFailed, fe.Error()).WithDetails(fields(err)).WithCause(err)
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return NewError(fiberErr.Code, statusCode(fiberErr.Code), fiberErr.Message).WithCause(err)
+	}
+
+	return NewError(fiber.StatusInternalServerError, CodeInternal, "Internal Server Error").WithCause(err)
+}
+
+// Fail sends e, as problem+json when configured so or asked for, in the usual envelope otherwise.
+// The request ID comes from the X-Request-ID header.
+func Fail(c *fiber.Ctx, e *Error) error {
+	if e.RequestID == "" {
+		e.RequestID = requestID(c)
+	}
+
+	message := e.Message
+	if e.Status >= fiber.StatusInternalServerError && debug && e.Err != nil {
+		message = e.Err.Error()
+	}
+	if e.Status == fiber.StatusServiceUnavailable {
+		c.Set(fiber.HeaderRetryAfter, "1")
+	}
+	c.Locals(LocalsKey, e)
+
+	if problem(c) {
+		body := fiber.Map{
+			"type":     "about:blank",
+			"title":    http.StatusText(e.Status),
+			"status":   e.Status,
+			"detail":   message,
+			"instance": c.OriginalURL(),
+			"code":     e.Code,
+		}
+		if e.Details != nil {
+			body["details"] = e.Details
+		}
+		if e.RequestID != "" {
+			body["request_id"] = e.RequestID
+		}
+		c.Status(e.Status)
+		if err := c.JSON(body); err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, MIMEProblemJSON)
+		return nil
+	}
+
+	body := fiber.Map{
+		"status":  false,
+		"code":    e.Code,
+		"message": message,
+		"data":    nil,
+	}
+	if e.Details != nil {
+		body["details"] = e.Details
+	}
+	if e.RequestID != "" {
+		body["request_id"] = e.RequestID
+	}
+	return c.Status(e.Status).JSON(body)
+}
+
+// problem tells whether to send problem+json, because it is the configured format or the client
+// accepts it and not the usual JSON.
+func problem(c *fiber.Ctx) bool {
+	if format == FormatProblem {
+		return true
+	}
+	return strings.Contains(c.Get(fiber.HeaderAccept), MIMEProblemJSON)
+}
+
+func requestID(c *fiber.Ctx) string {
+	if id := c.GetRespHeader(fiber.HeaderXRequestID); id != "" {
+		return id
+	}
+	return c.Get(fiber.HeaderXRequestID)
+}
+
+// fields are the messages by field of err, nil when it has none.
+func fields(err error) interface{} {
+	var fe fieldErrors
+	if !errors.As(err, &fe) || len(fe.Fields()) == 0 {
+		return nil
+	}
+	return fe.Fields()
+}
+
+// statusCode is the code for an error only known by its status.
+func statusCode(status int) string {
+	switch status {
+	case fiber.StatusBadRequest:
+		return CodeBadRequest
+	case fiber.StatusUnauthorized:
+		return CodeUnauthenticated
+	case fiber.StatusForbidden:
+		return CodePermissionDenied
+	case fiber.StatusNotFound:
+		return CodeRouteNotFound
+	case fiber.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case fiber.StatusConflict:
+		return CodeConflict
+	case fiber.StatusGone:
+		return CodeGone
+	case fiber.StatusRequestEntityTooLarge:
+		return CodeRequestTooLarge
+	case fiber.StatusRequestTimeout:
+		return CodeTimeout
+	case fiber.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case fiber.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	}
+	if status >= fiber.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
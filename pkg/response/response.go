@@ -1,48 +1,70 @@
 package response
 
 import (
-	"github.com/arif-x/sqlx-mysql-boilerplate/config"
 	"github.com/gofiber/fiber/v2"
 )
 
+// StatusChecksumMismatch is the status tus uses for an upload that does not match its checksum.
+const StatusChecksumMismatch = 460
+
+// InternalServerError sends err, a 500 unless From knows better, as for the database errors of a
+// duplicate or a deadlock.
 func InternalServerError(c *fiber.Ctx, err error) error {
-	if config.AppCfg().Debug == false {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  false,
-			"message": "Internal Server Error",
-			"data":    nil,
-		})
-	} else {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  false,
-			"message": err,
-			"data":    nil,
-		})
-	}
+	return Fail(c, From(err))
 }
 
 func BadRequest(c *fiber.Ctx, err error) error {
-	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-		"status":  false,
-		"message": "Bad Request",
-		"data":    nil,
-	})
+	message := "Bad Request"
+	if err != nil {
+		message = err.Error()
+	}
+	return Fail(c, NewError(fiber.StatusBadRequest, CodeBadRequest, message).WithCause(err))
+}
+
+// Invalid sends 422 with the failed rules of a request that did not validate, or 400 for a body that
+// could not be parsed. An *Error, such as the database failing during validation, is sent as is.
+func Invalid(c *fiber.Ctx, err error) error {
+	if e, ok := err.(*Error); ok {
+		return Fail(c, e)
+	}
+	if _, ok := err.(fieldErrors); !ok {
+		return BadRequest(c, err)
+	}
+	return Fail(c, From(err))
+}
+
+// UploadRejected sends status with the reason an upload field was rejected, also keyed by the field in details.
+func UploadRejected(c *fiber.Ctx, status int, field string, message string) error {
+	code := CodeUploadRejected
+	switch status {
+	case fiber.StatusRequestEntityTooLarge:
+		code = CodeUploadTooLarge
+	case fiber.StatusUnsupportedMediaType:
+		code = CodeUploadType
+	case StatusChecksumMismatch:
+		code = CodeUploadChecksum
+	}
+	return Fail(c, NewError(status, code, message).WithDetails(map[string][]string{field: {message}}))
 }
 
 func InvalidCredential(c *fiber.Ctx, err error) error {
-	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-		"status":  false,
-		"message": "Invalid Credential",
-		"data":    nil,
-	})
+	return Fail(c, NewError(fiber.StatusUnauthorized, CodeInvalidCredentials, "Invalid Credential").WithCause(err))
+}
+
+func Forbidden(c *fiber.Ctx, err error) error {
+	return Fail(c, NewError(fiber.StatusForbidden, CodePermissionDenied, err.Error()))
+}
+
+func Conflict(c *fiber.Ctx, err error) error {
+	return Fail(c, NewError(fiber.StatusConflict, CodeConflict, err.Error()))
+}
+
+func Gone(c *fiber.Ctx, err error) error {
+	return Fail(c, NewError(fiber.StatusGone, CodeGone, err.Error()))
 }
 
 func NotFound(c *fiber.Ctx, err error) error {
-	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-		"status":  false,
-		"message": "Not Found",
-		"data":    nil,
-	})
+	return Fail(c, NewError(fiber.StatusNotFound, CodeNotFound, "Not Found").WithCause(err))
 }
 
 func Index(c *fiber.Ctx, page int, limit int, count int, data interface{}) error {
@@ -105,3 +127,19 @@ func Destroy(c *fiber.Ctx, data interface{}) error {
 		"data":    data,
 	})
 }
+
+// MovedPermanently sends a 301 to location. Browsers get a plain redirect, API clients also get
+// the new location as redirect_to in the usual JSON body.
+func MovedPermanently(c *fiber.Ctx, location string) error {
+	if c.Accepts(fiber.MIMETextHTML, fiber.MIMEApplicationJSON) == fiber.MIMETextHTML {
+		return c.Redirect(location, fiber.StatusMovedPermanently)
+	}
+
+	c.Location(location)
+	return c.Status(fiber.StatusMovedPermanently).JSON(fiber.Map{
+		"status":      true,
+		"message":     "Moved Permanently",
+		"redirect_to": location,
+		"data":        nil,
+	})
+}
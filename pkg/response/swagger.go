@@ -18,9 +18,48 @@ type AuthWithPermissionResponse struct {
 	Permission []string `json:"permission"`
 }
 
+type MePermissionsResponse struct {
+	Status  bool                `json:"status"`
+	Message string              `json:"message"`
+	Data    map[string][]string `json:"data"`
+}
+
+type MeCanResponse struct {
+	Status  bool            `json:"status"`
+	Message string          `json:"message"`
+	Data    map[string]bool `json:"data"`
+}
+
 type ErrorResponse struct {
-	Status  bool   `json:"status" example:"false"`
-	Message string `json:"message"`
+	Status    bool        `json:"status" example:"false"`
+	Code      string      `json:"code" example:"RESOURCE_NOT_FOUND"`
+	Message   string      `json:"message"`
+	Data      interface{} `json:"data"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+type UploadErrorResponse struct {
+	Status    bool                `json:"status" example:"false"`
+	Code      string              `json:"code" example:"UPLOAD_TOO_LARGE"`
+	Message   string              `json:"message"`
+	Data      interface{}         `json:"data"`
+	Details   map[string][]string `json:"details"`
+	RequestID string              `json:"request_id,omitempty"`
+}
+
+type ValidationErrorResponse struct {
+	Status    bool                `json:"status" example:"false"`
+	Code      string              `json:"code" example:"VALIDATION_FAILED"`
+	Message   string              `json:"message" example:"The given data was invalid"`
+	Data      interface{}         `json:"data"`
+	Details   map[string][]string `json:"details"`
+	RequestID string              `json:"request_id,omitempty"`
+}
+
+type RedirectResponse struct {
+	Status     bool   `json:"status"`
+	Message    string `json:"message" example:"Moved Permanently"`
+	RedirectTo string `json:"redirect_to" example:"/api/v1/public/post/new-slug"`
 }
 
 type UserResponse struct {
@@ -44,6 +83,48 @@ type TagResponse struct {
 	Data    dashboard.Tag `json:"data"`
 }
 
+type MediaResponse struct {
+	Status  bool            `json:"status"`
+	Message string          `json:"message"`
+	Data    dashboard.Media `json:"data"`
+}
+
+type MediasResponse struct {
+	Status  bool              `json:"status"`
+	Message string            `json:"message"`
+	Data    []dashboard.Media `json:"data"`
+	Limit   int               `json:"limit"`
+	Page    int               `json:"page"`
+	Total   int               `json:"total"`
+}
+
+type UploadResponse struct {
+	Status  bool             `json:"status"`
+	Message string           `json:"message"`
+	Data    dashboard.Upload `json:"data"`
+}
+
+type CategoryResponse struct {
+	Status  bool               `json:"status"`
+	Message string             `json:"message"`
+	Data    dashboard.Category `json:"data"`
+}
+
+type CategoryShowResponse struct {
+	Status  bool                   `json:"status"`
+	Message string                 `json:"message"`
+	Data    dashboard.CategoryShow `json:"data"`
+}
+
+type CategoriesResponse struct {
+	Status  bool                 `json:"status"`
+	Message string               `json:"message"`
+	Data    []dashboard.Category `json:"data"`
+	Limit   int                  `json:"limit"`
+	Page    int                  `json:"page"`
+	Total   int                  `json:"total"`
+}
+
 type TagsResponse struct {
 	Status  bool            `json:"status"`
 	Message string          `json:"message"`
@@ -68,6 +149,47 @@ type PostsResponse struct {
 	Total   int              `json:"total"`
 }
 
+type PostRevisionResponse struct {
+	Status  bool                   `json:"status"`
+	Message string                 `json:"message"`
+	Data    dashboard.PostRevision `json:"data"`
+}
+
+type PostRevisionsResponse struct {
+	Status  bool                     `json:"status"`
+	Message string                   `json:"message"`
+	Data    []dashboard.PostRevision `json:"data"`
+}
+
+type PostRevisionDiffResponse struct {
+	Status  bool                       `json:"status"`
+	Message string                     `json:"message"`
+	Data    dashboard.PostRevisionDiff `json:"data"`
+}
+
+type CommentResponse struct {
+	Status  bool              `json:"status"`
+	Message string            `json:"message"`
+	Data    dashboard.Comment `json:"data"`
+}
+
+type CommentsResponse struct {
+	Status  bool                `json:"status"`
+	Message string              `json:"message"`
+	Data    []dashboard.Comment `json:"data"`
+	Limit   int                 `json:"limit"`
+	Page    int                 `json:"page"`
+	Total   int                 `json:"total"`
+}
+
+type CommentBulkResponse struct {
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+	Data    struct {
+		Affected int64 `json:"affected"`
+	} `json:"data"`
+}
+
 type RoleResponse struct {
 	Status  bool           `json:"status"`
 	Message string         `json:"message"`
@@ -113,6 +235,34 @@ type PublicPostsResponse struct {
 	Total   int           `json:"total"`
 }
 
+type PublicRankedPostsResponse struct {
+	Status  bool                `json:"status"`
+	Message string              `json:"message"`
+	Data    []public.RankedPost `json:"data"`
+}
+
+type PublicRelatedPostsResponse struct {
+	Status  bool                 `json:"status"`
+	Message string               `json:"message"`
+	Data    []public.RelatedPost `json:"data"`
+}
+
+type PublicCommentResponse struct {
+	Status  bool           `json:"status"`
+	Message string         `json:"message"`
+	Data    public.Comment `json:"data"`
+}
+
+type PublicCommentsResponse struct {
+	Status   bool             `json:"status"`
+	Message  string           `json:"message"`
+	Data     []public.Comment `json:"data"`
+	Limit    int              `json:"limit"`
+	Page     int              `json:"page"`
+	NextPage *int             `json:"next_page"`
+	Total    int              `json:"total"`
+}
+
 type PublicPostsByTagResponse struct {
 	Status  bool               `json:"status"`
 	Message string             `json:"message"`
@@ -131,6 +281,21 @@ type PublicPostsByUserResponse struct {
 	Total   int                 `json:"total"`
 }
 
+type PublicCategoryTreeResponse struct {
+	Status  bool                  `json:"status"`
+	Message string                `json:"message"`
+	Data    []public.CategoryNode `json:"data"`
+}
+
+type PublicPostsByCategoryResponse struct {
+	Status  bool                    `json:"status"`
+	Message string                  `json:"message"`
+	Data    public.CategoryWithPost `json:"data"`
+	Limit   int                     `json:"limit"`
+	Page    int                     `json:"page"`
+	Total   int                     `json:"total"`
+}
+
 type SyncPermissionResponse struct {
 	Status  bool                 `json:"status"`
 	Message string               `json:"message"`
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"time"
+
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/dashboard"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/logger"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/related"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/sitemap"
+)
+
+// publishScheduledPosts flips scheduled posts to published on every tick until done is closed.
+func publishScheduledPosts(interval time.Duration, done <-chan struct{}) {
+	logr := logger.GetLogger()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			repository := repo.NewPostRepo(database.GetDB())
+			published, err := repository.PublishScheduled()
+			if err != nil {
+				logr.Errorf("failed publishing scheduled posts. error: %v", err)
+				continue
+			}
+			if published > 0 {
+				sitemap.Invalidate()
+				related.Invalidate()
+				logr.Infof("published %d scheduled post(s)", published)
+			}
+		}
+	}
+}
@@ -9,7 +9,10 @@ import (
 	"github.com/arif-x/sqlx-mysql-boilerplate/app/http/middleware"
 	"github.com/arif-x/sqlx-mysql-boilerplate/config"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/file"
 	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/logger"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/viewcount"
 	route "github.com/arif-x/sqlx-mysql-boilerplate/route/api"
 	swagger "github.com/arsmn/fiber-swagger/v2"
 	"github.com/gofiber/fiber/v2"
@@ -27,6 +30,17 @@ func Serve() {
 		logr.Panicf("failed database setup. error: %v", err)
 	}
 
+	// answer violated constraints and deadlocks with proper statuses
+	response.Translate(database.Translate)
+
+	// set up file storage
+	if err := file.SetUp(config.StorageCfg()); err != nil {
+		logr.Panicf("failed storage setup. error: %v", err)
+	}
+	if config.StorageCfg().Driver == file.DriverLocal && config.StorageCfg().SigningKey == "" {
+		logr.Warnln("FILE_SIGNING_KEY is not set, links to private files are refused")
+	}
+
 	// Define Fiber config & app.
 	fiberCfg := config.FiberConfig()
 	app := fiber.New(fiberCfg)
@@ -36,10 +50,24 @@ func Serve() {
 
 	// Routes.
 	route.Auth(app)
+	route.Me(app)
 	route.Dashboard(app)
 	route.Public(app)
+	route.Feed(app)
+	route.Sitemap(app)
 	route.FileRoutes(app)
 	app.Get("/swagger/*", swagger.HandlerDefault)
+	route.NotFoundRoute(app)
+
+	// publish scheduled posts in the background
+	schedulerDone := make(chan struct{})
+	go publishScheduledPosts(appCfg.PostSchedulerInterval, schedulerDone)
+
+	// flush buffered post views in the background
+	viewcount.SetUp(appCfg.ViewDedupeWindow, appCfg.ViewDedupeLimit)
+	viewsDone := make(chan struct{})
+	viewsFlushed := make(chan struct{})
+	go flushPostViews(appCfg.ViewFlushInterval, viewsDone, viewsFlushed)
 
 	// signal channel to capture system calls
 	sigCh := make(chan os.Signal, 1)
@@ -50,6 +78,8 @@ func Serve() {
 		// capture sigterm and other system call here
 		<-sigCh
 		logr.Infoln("Shutting down server...")
+		close(schedulerDone)
+		close(viewsDone)
 		_ = app.Shutdown()
 	}()
 
@@ -59,4 +89,11 @@ func Serve() {
 		logr.Errorf("Oops... server is not running! error: %v", err)
 	}
 
+	// wait for the last views to be written
+	select {
+	case <-viewsDone:
+		<-viewsFlushed
+	default:
+	}
+
 }
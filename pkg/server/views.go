@@ -0,0 +1,42 @@
+package server
+
+import (
+	"time"
+
+	repo "github.com/arif-x/sqlx-mysql-boilerplate/app/repository/public"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/logger"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/viewcount"
+)
+
+// flushPostViews writes the buffered post views to the database on every tick. Once done is
+// closed it flushes one last time and closes flushed.
+func flushPostViews(interval time.Duration, done <-chan struct{}, flushed chan<- struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer close(flushed)
+
+	for {
+		select {
+		case <-done:
+			flushViews()
+			return
+		case <-ticker.C:
+			flushViews()
+		}
+	}
+}
+
+func flushViews() {
+	counter := viewcount.GetCounter()
+	views := counter.Take()
+	if len(views) == 0 {
+		return
+	}
+
+	repository := repo.NewPostViewRepo(database.GetDB())
+	if err := repository.AddViews(views, time.Now()); err != nil {
+		counter.Restore(views)
+		logger.GetLogger().Errorf("failed flushing post views. error: %v", err)
+	}
+}
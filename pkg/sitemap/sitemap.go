@@ -0,0 +1,138 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaxURLs is the most URLs the sitemap protocol allows in a single file.
+const MaxURLs = 50000
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type URL struct {
+	Loc     string
+	LastMod *time.Time
+}
+
+type xmlURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type xmlURLSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	URLs    []xmlURL `xml:"url"`
+}
+
+type xmlSitemap struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type xmlSitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []xmlSitemap `xml:"sitemap"`
+}
+
+// Generate renders urls into sitemap files keyed by file name. Up to MaxURLs it's a single
+// sitemap.xml, beyond that sitemap.xml is an index of sitemap-1.xml, sitemap-2.xml and so on.
+func Generate(base string, urls []URL) (map[string][]byte, error) {
+	if len(urls) <= MaxURLs {
+		body, err := urlSet(urls)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]byte{"sitemap.xml": body}, nil
+	}
+
+	files := map[string][]byte{}
+	index := xmlSitemapIndex{Xmlns: namespace}
+	for page := 1; (page-1)*MaxURLs < len(urls); page++ {
+		end := page * MaxURLs
+		if end > len(urls) {
+			end = len(urls)
+		}
+		chunk := urls[(page-1)*MaxURLs : end]
+
+		body, err := urlSet(chunk)
+		if err != nil {
+			return nil, err
+		}
+
+		name := "sitemap-" + strconv.Itoa(page) + ".xml"
+		files[name] = body
+		index.Sitemaps = append(index.Sitemaps, xmlSitemap{Loc: base + "/" + name, LastMod: lastMod(latest(chunk))})
+	}
+
+	body, err := xml.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files["sitemap.xml"] = append([]byte(xml.Header), body...)
+
+	return files, nil
+}
+
+func urlSet(urls []URL) ([]byte, error) {
+	set := xmlURLSet{Xmlns: namespace, URLs: make([]xmlURL, 0, len(urls))}
+	for _, url := range urls {
+		set.URLs = append(set.URLs, xmlURL{Loc: url.Loc, LastMod: lastMod(url.LastMod)})
+	}
+
+	body, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+func latest(urls []URL) *time.Time {
+	var last *time.Time
+	for _, url := range urls {
+		if url.LastMod != nil && (last == nil || url.LastMod.After(*last)) {
+			last = url.LastMod
+		}
+	}
+	return last
+}
+
+func lastMod(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+var cache struct {
+	sync.Mutex
+	files map[string][]byte
+}
+
+// Cached returns the cached sitemap files, building them with build when the cache is empty.
+func Cached(build func() (map[string][]byte, error)) (map[string][]byte, error) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	if cache.files == nil {
+		files, err := build()
+		if err != nil {
+			return nil, err
+		}
+		cache.files = files
+	}
+
+	return cache.files, nil
+}
+
+// Invalidate drops the cached sitemap so the next request rebuilds it. Call it whenever posts or tags change.
+func Invalidate() {
+	cache.Lock()
+	cache.files = nil
+	cache.Unlock()
+}
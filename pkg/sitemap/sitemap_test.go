@@ -0,0 +1,161 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"errors"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func urls(n int, lastMod func(i int) *time.Time) []URL {
+	list := make([]URL, n)
+	for i := range list {
+		list[i] = URL{Loc: "https://example.com/post/" + strconv.Itoa(i), LastMod: lastMod(i)}
+	}
+	return list
+}
+
+func noLastMod(int) *time.Time { return nil }
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name  string
+		urls  int
+		files []string
+		sizes map[string]int
+	}{
+		{"empty", 0, []string{"sitemap.xml"}, map[string]int{"sitemap.xml": 0}},
+		{"one", 1, []string{"sitemap.xml"}, map[string]int{"sitemap.xml": 1}},
+		{"exactly the limit", MaxURLs, []string{"sitemap.xml"}, map[string]int{"sitemap.xml": MaxURLs}},
+		{
+			"one over the limit", MaxURLs + 1,
+			[]string{"sitemap-1.xml", "sitemap-2.xml", "sitemap.xml"},
+			map[string]int{"sitemap-1.xml": MaxURLs, "sitemap-2.xml": 1},
+		},
+		{
+			"two full files", 2 * MaxURLs,
+			[]string{"sitemap-1.xml", "sitemap-2.xml", "sitemap.xml"},
+			map[string]int{"sitemap-1.xml": MaxURLs, "sitemap-2.xml": MaxURLs},
+		},
+		{
+			"three files", 2*MaxURLs + 10,
+			[]string{"sitemap-1.xml", "sitemap-2.xml", "sitemap-3.xml", "sitemap.xml"},
+			map[string]int{"sitemap-1.xml": MaxURLs, "sitemap-2.xml": MaxURLs, "sitemap-3.xml": 10},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			files, err := Generate("https://example.com", urls(test.urls, noLastMod))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := []string{}
+			for name := range files {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if len(names) != len(test.files) {
+				t.Fatalf("files = %v, want %v", names, test.files)
+			}
+			for i := range names {
+				if names[i] != test.files[i] {
+					t.Fatalf("files = %v, want %v", names, test.files)
+				}
+			}
+
+			for name, size := range test.sizes {
+				set := xmlURLSet{}
+				if err := xml.Unmarshal(files[name], &set); err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if len(set.URLs) != size {
+					t.Errorf("%s has %d URLs, want %d", name, len(set.URLs), size)
+				}
+			}
+
+			if len(test.files) > 1 {
+				index := xmlSitemapIndex{}
+				if err := xml.Unmarshal(files["sitemap.xml"], &index); err != nil {
+					t.Fatalf("sitemap.xml: %v", err)
+				}
+				if len(index.Sitemaps) != len(test.files)-1 {
+					t.Fatalf("index lists %d sitemaps, want %d", len(index.Sitemaps), len(test.files)-1)
+				}
+				for i, sitemap := range index.Sitemaps {
+					if want := "https://example.com/" + test.files[i]; sitemap.Loc != want {
+						t.Errorf("index entry %d = %q, want %q", i, sitemap.Loc, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateIndexLastMod(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastMod := func(i int) *time.Time {
+		if i%2 == 1 {
+			return nil
+		}
+		t := start.Add(time.Duration(i) * time.Minute)
+		return &t
+	}
+
+	files, err := Generate("https://example.com", urls(MaxURLs+3, lastMod))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := xmlSitemapIndex{}
+	if err := xml.Unmarshal(files["sitemap.xml"], &index); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		start.Add((MaxURLs - 2) * time.Minute).Format(time.RFC3339),
+		start.Add((MaxURLs + 2) * time.Minute).Format(time.RFC3339),
+	}
+	for i, sitemap := range index.Sitemaps {
+		if sitemap.LastMod != want[i] {
+			t.Errorf("lastmod of %s = %q, want %q", sitemap.Loc, sitemap.LastMod, want[i])
+		}
+	}
+}
+
+func TestCached(t *testing.T) {
+	Invalidate()
+	t.Cleanup(Invalidate)
+
+	builds := 0
+	build := func() (map[string][]byte, error) {
+		builds++
+		return map[string][]byte{"sitemap.xml": []byte(strconv.Itoa(builds))}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := Cached(build); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if builds != 1 {
+		t.Errorf("built %d times before Invalidate, want 1", builds)
+	}
+
+	Invalidate()
+	if files, _ := Cached(build); string(files["sitemap.xml"]) != "2" {
+		t.Errorf("Cached() after Invalidate = %q, want a rebuild", files["sitemap.xml"])
+	}
+
+	Invalidate()
+	failing := func() (map[string][]byte, error) { return nil, errors.New("down") }
+	if _, err := Cached(failing); err == nil {
+		t.Error("Cached() did not return the build error")
+	}
+	if files, _ := Cached(build); string(files["sitemap.xml"]) != "3" {
+		t.Errorf("a failed build was cached")
+	}
+}
@@ -0,0 +1,254 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Errors are the messages of every failed rule of a request, by field.
+type Errors map[string][]string
+
+func (errs Errors) Error() string {
+	return "The given data was invalid"
+}
+
+// Fields lets the error response list the messages by field.
+func (errs Errors) Fields() map[string][]string {
+	return errs
+}
+
+// Add appends a message to a field, for rules checked by hand.
+func (errs Errors) Add(field string, message string) Errors {
+	errs[field] = append(errs[field], message)
+	return errs
+}
+
+type (
+	ignoreKey  struct{}
+	failureKey struct{}
+)
+
+var (
+	validate   = newValidator()
+	identifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+	softDelete sync.Map
+)
+
+// ParseBody parses the request body into request and checks it, see Validate. Multipart bodies are
+// not bracket-aware, so list fields also take the values sent as "name[]".
+func ParseBody(c *fiber.Ctx, request interface{}) error {
+	if err := c.BodyParser(request); err != nil {
+		return err
+	}
+	bracketed(c, request)
+	return Validate(c, request)
+}
+
+// bracketed fills the []string fields of request a multipart body left empty with the values of
+// their form name followed by "[]".
+func bracketed(c *fiber.Ctx, request interface{}) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(request))
+	if value.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "" || name == "-" || field.Type != reflect.TypeOf([]string(nil)) || value.Field(i).Len() > 0 {
+			continue
+		}
+		if values, ok := form.Value[name+"[]"]; ok {
+			value.Field(i).Set(reflect.ValueOf(values))
+		}
+	}
+}
+
+// Validate checks request against the validate tags of its fields, returning Errors for the rules
+// that failed. Besides the usual rules, exists=table.column requires a row with the value and
+// unique=table.column no other row with it. Both leave soft deleted rows out and unique also the
+// row of the :id route parameter, the one being updated. When the database can't be asked, the
+// error returned is a *response.Error for the failure instead, not a rule that failed.
+func Validate(c *fiber.Ctx, request interface{}) error {
+	var failure error
+	ctx := context.WithValue(c.UserContext(), ignoreKey{}, c.Params("id"))
+	ctx = context.WithValue(ctx, failureKey{}, &failure)
+
+	err := validate.StructCtx(ctx, request)
+	if failure != nil {
+		return response.From(failure)
+	}
+	if err == nil {
+		return nil
+	}
+
+	failed, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	errs := Errors{}
+	for _, fe := range failed {
+		errs.Add(fe.Field(), message(fe))
+	}
+	return errs
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Name fields the way clients send them.
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+
+	v.RegisterValidationCtx("exists", func(ctx context.Context, fl validator.FieldLevel) bool {
+		value := fieldString(fl)
+		if value == "" {
+			return true
+		}
+		table, column := tableColumn(fl.Param())
+
+		condition, err := notDeleted(ctx, table)
+		if err != nil {
+			return fail(ctx, err)
+		}
+
+		var found int
+		query := fmt.Sprintf("SELECT COUNT(*) FROM `%s` WHERE `%s` = ?%s", table, column, condition)
+		if err := database.GetDB().QueryRowContext(ctx, query, value).Scan(&found); err != nil {
+			return fail(ctx, err)
+		}
+		return found > 0
+	})
+
+	v.RegisterValidationCtx("unique", func(ctx context.Context, fl validator.FieldLevel) bool {
+		value := fieldString(fl)
+		if value == "" {
+			return true
+		}
+		table, column := tableColumn(fl.Param())
+
+		condition, err := notDeleted(ctx, table)
+		if err != nil {
+			return fail(ctx, err)
+		}
+
+		query := fmt.Sprintf("SELECT COUNT(*) FROM `%s` WHERE `%s` = ?%s", table, column, condition)
+		args := []interface{}{value}
+		if ignore, _ := ctx.Value(ignoreKey{}).(string); ignore != "" {
+			query += " AND uuid != ?"
+			args = append(args, ignore)
+		}
+
+		var found int
+		if err := database.GetDB().QueryRowContext(ctx, query, args...).Scan(&found); err != nil {
+			return fail(ctx, err)
+		}
+		return found == 0
+	})
+
+	return v
+}
+
+// fail keeps the first database error a rule ran into for Validate to return, and fails the rule.
+func fail(ctx context.Context, err error) bool {
+	if failure, ok := ctx.Value(failureKey{}).(*error); ok && *failure == nil {
+		*failure = err
+	}
+	return false
+}
+
+// fieldString is the value of a field as the database compares it. UUIDs and other values with a
+// String method use it.
+func fieldString(fl validator.FieldLevel) string {
+	if stringer, ok := fl.Field().Interface().(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fl.Field().String()
+}
+
+// tableColumn splits the table.column parameter of a rule. They come from struct tags, a bad one is
+// a bug.
+func tableColumn(param string) (string, string) {
+	table, column, _ := strings.Cut(param, ".")
+	if !identifier.MatchString(table) || !identifier.MatchString(column) {
+		panic(fmt.Sprintf("validation: invalid table.column %q", param))
+	}
+	return table, column
+}
+
+// notDeleted is the condition leaving soft deleted rows out, for tables that have them.
+func notDeleted(ctx context.Context, table string) (string, error) {
+	deletes, ok := softDelete.Load(table)
+	if !ok {
+		var found int
+		query := `SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = 'deleted_at'`
+		if err := database.GetDB().QueryRowContext(ctx, query, table).Scan(&found); err != nil {
+			return "", err
+		}
+		deletes = found > 0
+		softDelete.Store(table, deletes)
+	}
+
+	if deletes.(bool) {
+		return " AND deleted_at IS NULL", nil
+	}
+	return "", nil
+}
+
+// message describes a failed rule.
+func message(fe validator.FieldError) string {
+	field := strings.ReplaceAll(fe.Field(), "_", " ")
+	items := fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map
+
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("The %s field is required", field)
+	case "email":
+		return fmt.Sprintf("The %s must be a valid email address", field)
+	case "uuid", "uuid4":
+		return fmt.Sprintf("The %s must be a valid UUID", field)
+	case "min":
+		if items {
+			return fmt.Sprintf("The %s must have at least %s items", field, fe.Param())
+		}
+		return fmt.Sprintf("The %s must be at least %s characters", field, fe.Param())
+	case "max":
+		if items {
+			return fmt.Sprintf("The %s must not have more than %s items", field, fe.Param())
+		}
+		return fmt.Sprintf("The %s must not be greater than %s characters", field, fe.Param())
+	case "oneof":
+		return fmt.Sprintf("The %s must be one of: %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "datetime":
+		return fmt.Sprintf("The %s must be a valid date", field)
+	case "alphanum":
+		return fmt.Sprintf("The %s must only contain letters and numbers", field)
+	case "exists":
+		return fmt.Sprintf("The selected %s is invalid", field)
+	case "unique":
+		return fmt.Sprintf("The %s has already been taken", field)
+	default:
+		return fmt.Sprintf("The %s is invalid", field)
+	}
+}
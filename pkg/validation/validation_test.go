@@ -0,0 +1,214 @@
+package validation
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/database"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+type row struct {
+	uuid    string
+	column  string
+	value   string
+	deleted bool
+}
+
+// fakeTables is the data the fake driver answers from. Tables named in softDeletes have a deleted_at
+// column, the others don't.
+var (
+	fakeTables = map[string][]row{
+		"tags": {
+			{uuid: "t1", column: "name", value: "go"},
+			{uuid: "t2", column: "name", value: "rust", deleted: true},
+		},
+		"categories": {
+			{uuid: "c1", column: "uuid", value: "c1"},
+			{uuid: "c2", column: "uuid", value: "c2", deleted: true},
+		},
+		"roles": {
+			{uuid: "r1", column: "name", value: "admin"},
+		},
+	}
+	softDeletes = map[string]bool{"tags": true, "categories": true}
+
+	countQuery = regexp.MustCompile("^SELECT COUNT\\(\\*\\) FROM `(\\w+)` WHERE `(\\w+)` = \\?( AND deleted_at IS NULL)?( AND uuid != \\?)?$")
+)
+
+type fakeDriver struct{}
+type fakeConn struct{}
+type fakeStmt struct{ query string }
+type fakeRows struct{ count int64 }
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if strings.Contains(s.query, "information_schema.columns") {
+		table := args[0].(string)
+		if table == "broken" {
+			return nil, errors.New("connection refused")
+		}
+		if softDeletes[table] {
+			return &fakeRows{1}, nil
+		}
+		return &fakeRows{0}, nil
+	}
+
+	match := countQuery.FindStringSubmatch(s.query)
+	if match == nil {
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+	table, column, notDeleted, ignoring := match[1], match[2], match[3] != "", match[4] != ""
+	if notDeleted && !softDeletes[table] {
+		return nil, fmt.Errorf("unknown column deleted_at in %s", table)
+	}
+
+	var count int64
+	for _, r := range fakeTables[table] {
+		if r.column != column || r.value != args[0] {
+			continue
+		}
+		if (notDeleted && r.deleted) || (ignoring && r.uuid == args[1]) {
+			continue
+		}
+		count++
+	}
+	return &fakeRows{count}, nil
+}
+
+func (*fakeRows) Columns() []string { return []string{"COUNT(*)"} }
+func (*fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.count < 0 {
+		return io.EOF
+	}
+	dest[0] = r.count
+	r.count = -1
+	return nil
+}
+
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{}, nil }
+func (fakeConnector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type tagRequest struct {
+	Name     string `json:"name" validate:"omitempty,unique=tags.name"`
+	Category string `json:"category" validate:"omitempty,exists=categories.uuid"`
+	Role     string `json:"role" validate:"omitempty,exists=roles.name"`
+	Broken   string `json:"broken" validate:"omitempty,exists=broken.uuid"`
+}
+
+// useFakeDB points the database at the fake driver until the test ends.
+func useFakeDB(t *testing.T) {
+	previous := database.GetDB().DB
+	database.GetDB().DB = sqlx.NewDb(sql.OpenDB(fakeConnector{}), "mysql")
+	t.Cleanup(func() { database.GetDB().DB = previous })
+}
+
+// validateBody runs ParseBody on body sent to path, returning its error.
+func validateBody(t *testing.T, path string, body string) error {
+	var err error
+	app := fiber.New()
+	app.Post("/tags/:id?", func(c *fiber.Ctx) error {
+		err = ParseBody(c, &tagRequest{})
+		return nil
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if _, testErr := app.Test(req); testErr != nil {
+		t.Fatal(testErr)
+	}
+	return err
+}
+
+func TestExistsUnique(t *testing.T) {
+	useFakeDB(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		failed map[string]string
+	}{
+		{"unique new value", "/tags", `{"name":"python"}`, nil},
+		{"unique taken", "/tags", `{"name":"go"}`, map[string]string{"name": "The name has already been taken"}},
+		{"unique taken by a soft deleted row", "/tags", `{"name":"rust"}`, nil},
+		{"unique taken by the row being updated", "/tags/t1", `{"name":"go"}`, nil},
+		{"unique taken by another row than the updated one", "/tags/t2", `{"name":"go"}`, map[string]string{"name": "The name has already been taken"}},
+		{"exists", "/tags", `{"category":"c1"}`, nil},
+		{"exists missing", "/tags", `{"category":"c3"}`, map[string]string{"category": "The selected category is invalid"}},
+		{"exists only soft deleted", "/tags", `{"category":"c2"}`, map[string]string{"category": "The selected category is invalid"}},
+		{"exists keeps the row being updated", "/tags/c1", `{"category":"c1"}`, nil},
+		{"exists in a table without soft deletes", "/tags", `{"role":"admin"}`, nil},
+		{
+			"several rules failed", "/tags", `{"name":"go","category":"c2","role":"editor"}`,
+			map[string]string{
+				"name":     "The name has already been taken",
+				"category": "The selected category is invalid",
+				"role":     "The selected role is invalid",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateBody(t, test.path, test.body)
+			if test.failed == nil {
+				if err != nil {
+					t.Fatalf("ParseBody() = %v, want nil", err)
+				}
+				return
+			}
+
+			var errs Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("ParseBody() = %v, want Errors", err)
+			}
+			if len(errs) != len(test.failed) {
+				t.Errorf("failed fields = %v, want %v", errs, test.failed)
+			}
+			for field, message := range test.failed {
+				if len(errs[field]) != 1 || errs[field][0] != message {
+					t.Errorf("errors of %s = %v, want [%s]", field, errs[field], message)
+				}
+			}
+		})
+	}
+}
+
+func TestExistsDatabaseFailure(t *testing.T) {
+	useFakeDB(t)
+
+	err := validateBody(t, "/tags", `{"name":"go","broken":"x"}`)
+
+	var e *response.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("ParseBody() = %v, want *response.Error", err)
+	}
+	if e.Status != fiber.StatusInternalServerError {
+		t.Errorf("Status = %d, want %d", e.Status, fiber.StatusInternalServerError)
+	}
+}
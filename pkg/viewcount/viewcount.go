@@ -0,0 +1,102 @@
+package viewcount
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Counter buffers post views in memory, counting a visitor once per post within a window,
+// so views can be written to the database in batches. It remembers at most limit visitors, the
+// longest seen ones are forgotten first.
+type Counter struct {
+	mu      sync.Mutex
+	window  time.Duration
+	limit   int
+	seen    map[string]*list.Element
+	order   *list.List
+	pending map[string]int
+}
+
+// visit is when a visitor last counted for a post.
+type visit struct {
+	key  string
+	last time.Time
+}
+
+func NewCounter(window time.Duration, limit int) *Counter {
+	return &Counter{
+		window:  window,
+		limit:   limit,
+		seen:    map[string]*list.Element{},
+		order:   list.New(),
+		pending: map[string]int{},
+	}
+}
+
+// Record counts a view of a post by a visitor unless the visitor already viewed it within the window.
+func (counter *Counter) Record(postUUID string, visitor string) {
+	key := postUUID + "|" + visitor
+	now := time.Now()
+
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	if element, ok := counter.seen[key]; ok {
+		if now.Sub(element.Value.(*visit).last) < counter.window {
+			return
+		}
+		counter.order.Remove(element)
+	}
+
+	counter.seen[key] = counter.order.PushBack(&visit{key: key, last: now})
+	for counter.limit > 0 && counter.order.Len() > counter.limit {
+		counter.forget(counter.order.Front())
+	}
+	counter.pending[postUUID]++
+}
+
+// Take hands over the buffered views per post and forgets visitors whose window has passed.
+func (counter *Counter) Take() map[string]int {
+	now := time.Now()
+
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	pending := counter.pending
+	counter.pending = map[string]int{}
+
+	// Visits are in the order they were seen, so the expired ones are at the front.
+	for element := counter.order.Front(); element != nil && now.Sub(element.Value.(*visit).last) >= counter.window; element = counter.order.Front() {
+		counter.forget(element)
+	}
+
+	return pending
+}
+
+// Restore puts views back after a failed flush so they're retried on the next one.
+func (counter *Counter) Restore(views map[string]int) {
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	for postUUID, count := range views {
+		counter.pending[postUUID] += count
+	}
+}
+
+func (counter *Counter) forget(element *list.Element) {
+	counter.order.Remove(element)
+	delete(counter.seen, element.Value.(*visit).key)
+}
+
+var defaultCounter = NewCounter(30*time.Minute, 100000)
+
+// SetUp replaces the default counter with one using the given dedupe window and visitor limit.
+func SetUp(window time.Duration, limit int) {
+	defaultCounter = NewCounter(window, limit)
+}
+
+// GetCounter returns the default counter.
+func GetCounter() *Counter {
+	return defaultCounter
+}
@@ -12,7 +12,8 @@ func Auth(a *fiber.App) {
 	auth.Post("/register", controllers.Register)
 	auth.Post("/login", controllers.Login)
 
-	need_auth := a.Group("/api/v1/auth", middleware.JWTProtected())
+	need_auth := a.Group("/api/v1/auth", middleware.JWTProtected(), middleware.AuditImpersonation())
 	need_auth.Post("/send-email", controllers.SendEmail)
 	need_auth.Get("/verify/:token", controllers.Verify)
+	need_auth.Post("/impersonate/stop", controllers.StopImpersonation)
 }
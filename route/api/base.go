@@ -1,15 +1,14 @@
 package api
 
 import (
-	"github.com/gofiber/fiber"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/response"
+	"github.com/gofiber/fiber/v2"
 )
 
 func NotFoundRoute(a *fiber.App) {
 	a.Use(
 		func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"msg": "sorry, endpoint is not found",
-			})
+			return response.NewError(fiber.StatusNotFound, response.CodeRouteNotFound, "Sorry, endpoint is not found")
 		},
 	)
 }
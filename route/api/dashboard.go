@@ -7,7 +7,7 @@ import (
 )
 
 func Dashboard(a *fiber.App) {
-	dashboard := a.Group("/api/v1/dashboard", middleware.JWTProtected(), middleware.Email(), middleware.IsActive())
+	dashboard := a.Group("/api/v1/dashboard", middleware.JWTProtected(), middleware.Email(), middleware.IsActive(), middleware.AuditImpersonation())
 
 	user := dashboard.Group("/user")
 	user.Get("/", middleware.Permission("user-index"), controllers.UserIndex)
@@ -15,6 +15,7 @@ func Dashboard(a *fiber.App) {
 	user.Post("/", middleware.Permission("user-store"), controllers.UserStore)
 	user.Put("/:id", middleware.Permission("user-update"), controllers.UserUpdate)
 	user.Delete("/:id", middleware.Permission("user-destroy"), controllers.UserDestroy)
+	user.Post("/:id/impersonate", middleware.Permission("user-impersonate"), controllers.UserImpersonate)
 
 	tag := dashboard.Group("/tags")
 	tag.Get("/", middleware.Permission("tags-index"), controllers.TagIndex)
@@ -23,13 +24,49 @@ func Dashboard(a *fiber.App) {
 	tag.Put("/:id", middleware.Permission("tags-update"), controllers.TagUpdate)
 	tag.Delete("/:id", middleware.Permission("tags-destroy"), controllers.TagDestroy)
 
+	category := dashboard.Group("/category")
+	category.Get("/", middleware.Permission("category-index"), controllers.CategoryIndex)
+	category.Get("/:id", middleware.Permission("category-show"), controllers.CategoryShow)
+	category.Post("/", middleware.Permission("category-store"), controllers.CategoryStore)
+	category.Put("/:id", middleware.Permission("category-update"), controllers.CategoryUpdate)
+	category.Delete("/:id", middleware.Permission("category-destroy"), controllers.CategoryDestroy)
+
+	media := dashboard.Group("/media")
+	media.Get("/", middleware.Permission("media-index"), controllers.MediaIndex)
+	media.Get("/:id", middleware.Permission("media-show"), controllers.MediaShow)
+	media.Post("/", middleware.Permission("media-store"), controllers.MediaStore)
+	media.Put("/:id", middleware.Permission("media-update"), controllers.MediaUpdate)
+	media.Delete("/:id", middleware.Permission("media-destroy"), controllers.MediaDestroy)
+
+	upload := dashboard.Group("/uploads", middleware.Permission("media-store"))
+	upload.Options("/", controllers.UploadOptions)
+	upload.Post("/", controllers.UploadStore)
+	upload.Head("/:id", controllers.UploadHead)
+	upload.Get("/:id", controllers.UploadShow)
+	upload.Patch("/:id", controllers.UploadPatch)
+	upload.Delete("/:id", controllers.UploadDestroy)
+
 	post := dashboard.Group("/post")
 	post.Get("/", middleware.Permission("post-index"), controllers.PostIndex)
 	post.Get("/:id", middleware.Permission("post-show"), controllers.PostShow)
 	post.Post("/", middleware.Permission("post-store"), controllers.PostStore)
 	post.Put("/:id", middleware.Permission("post-update"), controllers.PostUpdate)
+	post.Put("/:id/status", middleware.Permission("post-update"), controllers.PostStatus)
+	post.Get("/:id/revision", middleware.Permission("post-show"), controllers.PostRevisionIndex)
+	post.Get("/:id/revision/:revision", middleware.Permission("post-show"), controllers.PostRevisionShow)
+	post.Get("/:id/revision/:revision/diff", middleware.Permission("post-show"), controllers.PostRevisionDiff)
+	post.Post("/:id/revision/:revision/restore", middleware.Permission("post-update"), controllers.PostRevisionRestore)
 	post.Delete("/:id", middleware.Permission("post-destroy"), controllers.PostDestroy)
 
+	comment := dashboard.Group("/comment")
+	comment.Get("/", middleware.Permission("comment-index"), controllers.CommentIndex)
+	comment.Post("/bulk", middleware.Permission("comment-moderate"), controllers.CommentBulk)
+	comment.Get("/:id", middleware.Permission("comment-show"), controllers.CommentShow)
+	comment.Put("/:id", middleware.Permission("comment-update"), controllers.CommentUpdate)
+	comment.Put("/:id/approve", middleware.Permission("comment-moderate"), controllers.CommentApprove)
+	comment.Put("/:id/reject", middleware.Permission("comment-moderate"), controllers.CommentReject)
+	comment.Delete("/:id", middleware.Permission("comment-destroy"), controllers.CommentDestroy)
+
 	role := dashboard.Group("/role")
 	role.Get("/", middleware.Permission("role-index"), controllers.RoleIndex)
 	role.Get("/:id", middleware.Permission("role-show"), controllers.RoleShow)
@@ -0,0 +1,12 @@
+package api
+
+import (
+	controllers "github.com/arif-x/sqlx-mysql-boilerplate/app/http/controller/public"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Feed(a *fiber.App) {
+	a.Get("/feed.xml", controllers.RSSFeed)
+	a.Get("/atom.xml", controllers.AtomFeed)
+	a.Get("/feed.json", controllers.JSONFeed)
+}
@@ -1,7 +1,18 @@
 package api
 
-import "github.com/gofiber/fiber/v2"
+import (
+	controllers "github.com/arif-x/sqlx-mysql-boilerplate/app/http/controller/public"
+	"github.com/arif-x/sqlx-mysql-boilerplate/config"
+	"github.com/arif-x/sqlx-mysql-boilerplate/pkg/file"
+	"github.com/gofiber/fiber/v2"
+)
 
+// FileRoutes serves uploaded files from disk when they are kept by the local storage driver.
+// Private files are only served through signed URLs.
 func FileRoutes(a *fiber.App) {
-	a.Static("/upload/post", "./upload/post")
+	if local, ok := file.GetStorage().(*file.LocalStorage); ok {
+		prefix := config.StorageCfg().LocalURLPrefix
+		a.Get(prefix+"/"+file.PrivatePrefix+"*", controllers.PrivateFile)
+		a.Static(prefix, local.Root())
+	}
 }
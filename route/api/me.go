@@ -0,0 +1,14 @@
+package api
+
+import (
+	controllers "github.com/arif-x/sqlx-mysql-boilerplate/app/http/controller/auth"
+	"github.com/arif-x/sqlx-mysql-boilerplate/app/http/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Me(a *fiber.App) {
+	me := a.Group("/api/v1/me", middleware.JWTProtected(), middleware.AuditImpersonation())
+
+	me.Get("/permissions", controllers.MePermissions)
+	me.Post("/can", controllers.MeCan)
+}
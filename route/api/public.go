@@ -2,6 +2,7 @@ package api
 
 import (
 	controllers "github.com/arif-x/sqlx-mysql-boilerplate/app/http/controller/public"
+	"github.com/arif-x/sqlx-mysql-boilerplate/app/http/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -9,7 +10,21 @@ func Public(a *fiber.App) {
 	public := a.Group("/api/v1/public")
 
 	public.Get("/post", controllers.PostIndex)
+	public.Get("/post/popular", controllers.PostPopular)
+	public.Get("/post/trending", controllers.PostTrending)
+	public.Get("/post/category/:slug", controllers.CategoryPost)
 	public.Get("/post/tag/:slug", controllers.TagPost)
+	public.Get("/post/tag/:slug/feed.xml", controllers.TagFeed(controllers.FeedRSS))
+	public.Get("/post/tag/:slug/atom.xml", controllers.TagFeed(controllers.FeedAtom))
+	public.Get("/post/tag/:slug/feed.json", controllers.TagFeed(controllers.FeedJSON))
 	public.Get("/post/user/:username", controllers.UserPost)
+	public.Get("/post/user/:username/feed.xml", controllers.UserFeed(controllers.FeedRSS))
+	public.Get("/post/user/:username/atom.xml", controllers.UserFeed(controllers.FeedAtom))
+	public.Get("/post/user/:username/feed.json", controllers.UserFeed(controllers.FeedJSON))
 	public.Get("/post/:slug", controllers.PostShow)
+	public.Get("/post/:slug/related", controllers.PostRelated)
+	public.Get("/post/:slug/comments", controllers.PostComments)
+	public.Post("/post/:slug/comments", middleware.JWTOptional(), middleware.CommentLimiter(), middleware.AuditImpersonation(), controllers.PostCommentStore)
+
+	public.Get("/category/tree", controllers.CategoryTree)
 }
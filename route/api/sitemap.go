@@ -0,0 +1,11 @@
+package api
+
+import (
+	controllers "github.com/arif-x/sqlx-mysql-boilerplate/app/http/controller/public"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Sitemap(a *fiber.App) {
+	a.Get("/sitemap.xml", controllers.Sitemap)
+	a.Get("/sitemap-:page.xml", controllers.Sitemap)
+}